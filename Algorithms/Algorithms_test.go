@@ -22,6 +22,7 @@
 package Algorithms
 
 import (
+	"context"
 	"github.com/smohr1824/Networks/Core"
 	"testing"
 )
@@ -103,4 +104,97 @@ func TestBipartiteBasic(t *testing.T) {
 	if !consistent {
 		t.Error("At least one inconsistent member was found in one of the sets")
 	}
-}
\ No newline at end of file
+}
+
+func TestBipartiteComponents(t *testing.T) {
+	// two even cycles and an isolated vertex
+	G := Core.NewNetwork(false)
+	for i := uint32(0); i < 6; i++ {
+		_ = G.AddEdge(i, (i+1)%6, 1.0)
+		_ = G.AddEdge(10+i, 10+(i+1)%6, 1.0)
+	}
+	G.AddVertex(20)
+
+	result, err := ConcurrentBipartiteContext(context.Background(), G, 3, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsBipartite || len(result.R)+len(result.B) != 13 {
+		t.Fatalf("Expected every vertex of a bipartite network colored, found %d", len(result.R)+len(result.B))
+	}
+	inR := make(map[uint32]bool)
+	for _, v := range result.R {
+		inR[v] = true
+	}
+	for _, v := range G.Vertices(false) {
+		for n := range G.GetNeighbors(v) {
+			if inR[v] == inR[n] {
+				t.Errorf("Adjacent vertices %d and %d have the same color", v, n)
+			}
+		}
+	}
+}
+
+// checks that cycle is an odd closed walk through distinct vertices of G
+func checkOddCycle(t *testing.T, G *Core.Network, cycle []uint32) {
+	if len(cycle)%2 == 0 {
+		t.Errorf("Expected an odd cycle, found %v", cycle)
+	}
+	seen := make(map[uint32]bool)
+	for i, v := range cycle {
+		if seen[v] {
+			t.Errorf("Vertex %d repeated in cycle %v", v, cycle)
+		}
+		seen[v] = true
+		if !G.HasEdge(v, cycle[(i+1)%len(cycle)]) {
+			t.Errorf("Cycle %v uses a missing edge", cycle)
+		}
+	}
+}
+
+func TestBipartiteOddCycle(t *testing.T) {
+	// a bipartite component followed by a component containing a 7-cycle with pendant vertices
+	pendants := Core.NewNetwork(false)
+	_ = pendants.AddEdge(0, 1, 1.0)
+	for i := uint32(0); i < 7; i++ {
+		_ = pendants.AddEdge(10+i, 10+(i+1)%7, 1.0)
+		_ = pendants.AddEdge(10+i, 20+i, 1.0)
+	}
+	// bare odd cycles, in which every vertex is colored before the conflicting colorings come back
+	triangle := Core.NewNetwork(false)
+	pentagon := Core.NewNetwork(false)
+	for i := uint32(0); i < 5; i++ {
+		if i < 3 {
+			_ = triangle.AddEdge(i, (i+1)%3, 1.0)
+		}
+		_ = pentagon.AddEdge(i, (i+1)%5, 1.0)
+	}
+
+	for _, G := range []*Core.Network{pendants, triangle, pentagon} {
+		for trial := 0; trial < 20; trial++ {
+			result, err := ConcurrentBipartiteContext(context.Background(), G, 1+trial%4, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.IsBipartite {
+				t.Fatalf("Network of order %d with an odd cycle found to be bipartite", G.Order())
+			}
+			checkOddCycle(t, G, result.OddCycle)
+		}
+		if isIt, _, _ := ConcurrentBipartite(G, 2); isIt {
+			t.Errorf("Network with an odd cycle found to be bipartite")
+		}
+	}
+}
+
+func TestBipartiteCancel(t *testing.T) {
+	G := Core.NewNetwork(false)
+	for i := uint32(0); i < 20000; i++ {
+		_ = G.AddEdge(i, i+1, 1.0)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ConcurrentBipartiteContext(ctx, G, 2, nil); err != context.Canceled {
+		t.Errorf("Expected cancellation, found %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Articulation points, bridges, and biconnected components after Hopcroft and Tarjan, Efficient algorithms for graph manipulation, CACM 16(6), 1973
+// Directed networks are treated as undirected, so a directed elementary layer from MultilayerNetwork.GetLayer may be analyzed directly.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+)
+
+// The blocks (maximal biconnected subgraphs) of a network and the articulation points joining them.  Isolated vertices belong to no block.
+// In the block-cut tree every block is adjacent to the articulation points it contains.
+type BlockCutTree struct {
+	Blocks             [][]uint32       // vertices of each block, sorted
+	BlockEdges         [][]Edge         // edges of each block
+	ArticulationPoints []uint32         // sorted
+	BlockCutVertices   [][]uint32       // articulation points in each block
+	CutVertexBlocks    map[uint32][]int // blocks containing each articulation point
+}
+
+// Returns the vertices whose removal disconnects their component
+func ArticulationPoints(G *Core.Network) ([]uint32, error) {
+	tree, err := BiconnectedComponents(G)
+	if err != nil {
+		return nil, err
+	}
+	return tree.ArticulationPoints, nil
+}
+
+// Returns the edges whose removal disconnects their component, with From < To
+func Bridges(G *Core.Network) ([]Edge, error) {
+	tree, err := BiconnectedComponents(G)
+	if err != nil {
+		return nil, err
+	}
+	return tree.Bridges(), nil
+}
+
+// Decomposes the network into blocks.  Blocks are numbered in the order a depth-first search from the lowest vertex id completes them.
+func BiconnectedComponents(G *Core.Network) (*BlockCutTree, error) {
+	return BiconnectedComponentsContext(context.Background(), G, nil)
+}
+
+// As BiconnectedComponents, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as each block is completed
+func BiconnectedComponentsContext(ctx context.Context, G *Core.Network, progress ProgressFunc) (*BlockCutTree, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+
+	retVal := new(BlockCutTree)
+	retVal.Blocks = make([][]uint32, 0)
+	retVal.BlockEdges = make([][]Edge, 0)
+	retVal.CutVertexBlocks = make(map[uint32][]int)
+
+	discovered := make(map[uint32]int, G.Order())
+	low := make(map[uint32]int, G.Order())
+	isCut := make(map[uint32]bool)
+	clock := 0
+
+	type frame struct {
+		vertex    uint32
+		parent    uint32
+		neighbors []traversalEdge
+		pos       int
+	}
+
+	reporter := newProgressReporter("BiconnectedComponentsContext", progress)
+	for _, root := range G.Vertices(true) {
+		if _, seen := discovered[root]; seen {
+			continue
+		}
+		discovered[root] = clock
+		low[root] = clock
+		clock++
+		rootChildren := 0
+		edges := make([]Edge, 0)
+		stack := []*frame{{vertex: root, parent: root, neighbors: traversalNeighbors(G, root, AnyDirection)}}
+
+		for len(stack) > 0 {
+			if cancelled(ctx) {
+				return nil, ctx.Err()
+			}
+			top := stack[len(stack)-1]
+			if top.pos < len(top.neighbors) {
+				w := top.neighbors[top.pos].to
+				top.pos++
+				if w == top.parent {
+					continue
+				}
+				if d, seen := discovered[w]; !seen {
+					edges = append(edges, Edge{From: top.vertex, To: w})
+					discovered[w] = clock
+					low[w] = clock
+					clock++
+					if top.vertex == root {
+						rootChildren++
+					}
+					stack = append(stack, &frame{vertex: w, parent: top.vertex, neighbors: traversalNeighbors(G, w, AnyDirection)})
+				} else if d < discovered[top.vertex] {
+					// back edge
+					edges = append(edges, Edge{From: top.vertex, To: w})
+					if d < low[top.vertex] {
+						low[top.vertex] = d
+					}
+				}
+				continue
+			}
+
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				break
+			}
+			u := top.vertex
+			p := top.parent
+			if low[u] < low[p] {
+				low[p] = low[u]
+			}
+			if low[u] >= discovered[p] {
+				// p separates the subtree at u; the edges above (p, u) on the stack form a block
+				if p != root || rootChildren > 1 {
+					isCut[p] = true
+				}
+				i := len(edges) - 1
+				for edges[i].From != p || edges[i].To != u {
+					i--
+				}
+				retVal.addBlock(G, edges[i:])
+				edges = edges[:i]
+				reporter.emit(len(retVal.Blocks), -1)
+			}
+		}
+	}
+
+	retVal.ArticulationPoints = make([]uint32, 0, len(isCut))
+	for v := range isCut {
+		retVal.ArticulationPoints = append(retVal.ArticulationPoints, v)
+	}
+	sort.Slice(retVal.ArticulationPoints, func(i, j int) bool { return retVal.ArticulationPoints[i] < retVal.ArticulationPoints[j] })
+
+	retVal.BlockCutVertices = make([][]uint32, len(retVal.Blocks))
+	for i, block := range retVal.Blocks {
+		retVal.BlockCutVertices[i] = make([]uint32, 0)
+		for _, v := range block {
+			if isCut[v] {
+				retVal.BlockCutVertices[i] = append(retVal.BlockCutVertices[i], v)
+				retVal.CutVertexBlocks[v] = append(retVal.CutVertexBlocks[v], i)
+			}
+		}
+	}
+	return retVal, nil
+}
+
+// Blocks consisting of a single edge are bridges
+func (tree *BlockCutTree) Bridges() []Edge {
+	retVal := make([]Edge, 0)
+	for _, edges := range tree.BlockEdges {
+		if len(edges) == 1 {
+			retVal = append(retVal, edges[0])
+		}
+	}
+	sortEdges(retVal)
+	return retVal
+}
+
+// Returns the block-cut tree (a forest if the network is disconnected) as an undirected network.  Block i is vertex i; articulation point
+// ArticulationPoints[j] is vertex len(Blocks) + j.
+func (tree *BlockCutTree) Network() *Core.Network {
+	retVal := Core.NewNetwork(false)
+	for i := range tree.Blocks {
+		retVal.AddVertex(uint32(i))
+	}
+	for j, v := range tree.ArticulationPoints {
+		id := uint32(len(tree.Blocks) + j)
+		retVal.AddVertex(id)
+		for _, block := range tree.CutVertexBlocks[v] {
+			_ = retVal.AddEdge(uint32(block), id, 1.0)
+		}
+	}
+	return retVal
+}
+
+func (tree *BlockCutTree) addBlock(G *Core.Network, edges []Edge) {
+	vertices := make(map[uint32]bool)
+	blockEdges := make([]Edge, len(edges))
+	for i, edge := range edges {
+		vertices[edge.From] = true
+		vertices[edge.To] = true
+		blockEdges[i] = undirectedEdge(G, edge.From, edge.To)
+	}
+	sortEdges(blockEdges)
+
+	block := make([]uint32, 0, len(vertices))
+	for v := range vertices {
+		block = append(block, v)
+	}
+	sort.Slice(block, func(i, j int) bool { return block[i] < block[j] })
+	tree.Blocks = append(tree.Blocks, block)
+	tree.BlockEdges = append(tree.BlockEdges, blockEdges)
+}
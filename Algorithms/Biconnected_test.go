@@ -0,0 +1,105 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"testing"
+)
+
+// triangles 1-2-3 and 3-4-5 sharing vertex 3, with a pendant 6 on 5
+func makeBowtie(directed bool) *Core.Network {
+	G := Core.NewNetwork(directed)
+	_ = G.AddEdge(1, 2, 1.0)
+	_ = G.AddEdge(2, 3, 1.0)
+	_ = G.AddEdge(3, 1, 1.0)
+	_ = G.AddEdge(3, 4, 1.0)
+	_ = G.AddEdge(4, 5, 1.0)
+	_ = G.AddEdge(5, 3, 1.0)
+	_ = G.AddEdge(5, 6, 2.0)
+	return G
+}
+
+func TestBiconnectedComponents(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		G := makeBowtie(directed)
+		G.AddVertex(7)
+		tree, err := BiconnectedComponents(G)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tree.ArticulationPoints) != 2 || tree.ArticulationPoints[0] != 3 || tree.ArticulationPoints[1] != 5 {
+			t.Errorf("Expected articulation points [3 5], found %v", tree.ArticulationPoints)
+		}
+		if len(tree.Blocks) != 3 {
+			t.Fatalf("Expected 3 blocks, found %v", tree.Blocks)
+		}
+		bridges := tree.Bridges()
+		if len(bridges) != 1 || bridges[0].From != 5 || bridges[0].To != 6 || bridges[0].Weight != 2.0 {
+			t.Errorf("Expected the single bridge 5-6, found %v", bridges)
+		}
+		if len(tree.CutVertexBlocks[3]) != 2 || len(tree.CutVertexBlocks[5]) != 2 {
+			t.Errorf("Expected each articulation point in 2 blocks, found %v", tree.CutVertexBlocks)
+		}
+
+		bct := tree.Network()
+		if bct.Order() != 5 || bct.Size() != 4 {
+			t.Errorf("Expected a block-cut tree of order 5 and size 4, found %d and %d", bct.Order(), bct.Size())
+		}
+	}
+
+	// a cycle has no articulation points or bridges
+	C := Core.NewNetwork(false)
+	for i := uint32(0); i < 5; i++ {
+		_ = C.AddEdge(i, (i+1)%5, 1.0)
+	}
+	points, _ := ArticulationPoints(C)
+	bridges, _ := Bridges(C)
+	if len(points) != 0 || len(bridges) != 0 {
+		t.Errorf("Expected no articulation points or bridges in a cycle, found %v and %v", points, bridges)
+	}
+}
+
+func TestBiconnectedLayer(t *testing.T) {
+	M := Core.NewMultilayerNetwork([]string{"kind"}, [][]string{{"power", "water"}}, false)
+	if _, err := M.AddElementaryLayer("power", makeBowtie(false)); err != nil {
+		t.Fatal(err)
+	}
+	path := Core.NewNetwork(false)
+	_ = path.AddEdge(1, 2, 1.0)
+	_ = path.AddEdge(2, 3, 1.0)
+	if _, err := M.AddElementaryLayer("water", path); err != nil {
+		t.Fatal(err)
+	}
+
+	points, err := ArticulationPoints(M.GetLayer("power"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Errorf("Expected 2 articulation points in the power layer, found %v", points)
+	}
+	bridges, _ := Bridges(M.GetLayer("water"))
+	if len(bridges) != 2 {
+		t.Errorf("Expected 2 bridges in the water layer, found %v", bridges)
+	}
+}
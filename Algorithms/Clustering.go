@@ -0,0 +1,317 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Clustering coefficients, transitivity, and triangle counting
+// Directed networks are treated as undirected: two vertices are adjacent if an edge runs either way, and where edges run both ways
+// the weight is the mean of the two.
+// Weighted coefficients follow Barrat, A., Barthélemy, M., Pastor-Satorras, R. and Vespignani, A., "The architecture of complex weighted networks",
+// PNAS 101, 3747-3752, 2004, and Onnela, J.-P., Saramäki, J., Kertész, J. and Kaski, K., "Intensity and coherence of motifs in weighted
+// complex networks", Physical Review E 71, 065103, 2005.
+// Concurrent versions divide the sorted vertices into contiguous ranges exactly as ConcurrentSLPA does, one goroutine per range.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+)
+
+type ClusteringVariant int
+
+type partitionResult struct {
+	partition int
+	value     float64
+}
+
+const (
+	Unweighted ClusteringVariant = iota
+	Barrat
+	Onnela
+)
+
+// Local clustering coefficient of a vertex: the fraction of pairs of its neighbors that are themselves adjacent, or the weighted
+// analogue for the Barrat and Onnela variants.  Vertices with fewer than two neighbors, and the weighted variants of vertices whose
+// edges all have weight zero, have a coefficient of zero.
+func LocalClustering(G *Core.Network, vertex uint32, variant ClusteringVariant) float64 {
+	retVal, _ := LocalClusteringContext(context.Background(), G, vertex, variant, nil)
+	return retVal
+}
+
+// As LocalClustering, but stops with the context's error if ctx is cancelled.  The Onnela variant scans every edge for the maximum weight;
+// progress, if not nil, is reported as phase 1 when that scan completes and phase 2 when the coefficient is computed.
+func LocalClusteringContext(ctx context.Context, G *Core.Network, vertex uint32, variant ClusteringVariant, progress ProgressFunc) (float64, error) {
+	reporter := newProgressReporter("LocalClusteringContext", progress)
+	maxWeight := 0.0
+	if variant == Onnela {
+		var err error
+		if maxWeight, err = maxUndirectedWeight(ctx, G); err != nil {
+			return 0.0, err
+		}
+		reporter.emit(1, -1)
+	}
+	if err := ctx.Err(); err != nil {
+		return 0.0, err
+	}
+	retVal := localClustering(G, vertex, variant, maxWeight)
+	reporter.emit(2, -1)
+	return retVal, nil
+}
+
+// Mean of the local clustering coefficients of all vertices, computed concurrently
+func AverageClustering(G *Core.Network, variant ClusteringVariant, concurrentCount int) float64 {
+	retVal, _ := AverageClusteringContext(context.Background(), G, variant, concurrentCount, nil)
+	return retVal
+}
+
+// As AverageClustering, but stops the goroutines and returns the context's error if ctx is cancelled, and reports progress, if not nil,
+// as each partition finishes
+func AverageClusteringContext(ctx context.Context, G *Core.Network, variant ClusteringVariant, concurrentCount int, progress ProgressFunc) (float64, error) {
+	order := G.Order()
+	if order == 0 {
+		return 0.0, nil
+	}
+	maxWeight := 0.0
+	if variant == Onnela {
+		var err error
+		if maxWeight, err = maxUndirectedWeight(ctx, G); err != nil {
+			return 0.0, err
+		}
+	}
+
+	sums, err := runPartitioned(ctx, G, concurrentCount, newProgressReporter("AverageClusteringContext", progress), func(v uint32) float64 {
+		return localClustering(G, v, variant, maxWeight)
+	})
+	if err != nil {
+		return 0.0, err
+	}
+	total := 0.0
+	for _, sum := range sums {
+		total += sum
+	}
+	return total / float64(order), nil
+}
+
+// Global transitivity: three times the number of triangles divided by the number of connected triples
+func Transitivity(G *Core.Network, concurrentCount int) float64 {
+	retVal, _ := TransitivityContext(context.Background(), G, concurrentCount, nil)
+	return retVal
+}
+
+// As Transitivity, but stops the goroutines and returns the context's error if ctx is cancelled, and reports progress, if not nil,
+// as each partition finishes counting triangles
+func TransitivityContext(ctx context.Context, G *Core.Network, concurrentCount int, progress ProgressFunc) (float64, error) {
+	triangles, err := countTriangles(ctx, G, concurrentCount, newProgressReporter("TransitivityContext", progress))
+	if err != nil {
+		return 0.0, err
+	}
+	triples := 0.0
+	for _, v := range G.Vertices(false) {
+		k := float64(len(undirectedNeighbors(G, v)))
+		triples += k * (k - 1) / 2
+	}
+	if triples == 0 {
+		return 0.0, nil
+	}
+	return 3 * float64(triangles) / triples, nil
+}
+
+// Number of triangles containing vertex
+func Triangles(G *Core.Network, vertex uint32) int {
+	neighbors := undirectedNeighbors(G, vertex)
+	retVal := 0
+	for u := range neighbors {
+		for w := range undirectedNeighbors(G, u) {
+			if u < w {
+				if _, ok := neighbors[w]; ok {
+					retVal++
+				}
+			}
+		}
+	}
+	return retVal
+}
+
+// Exact number of triangles in the network.  Each goroutine counts the triangles whose lowest vertex id lies in its partition,
+// so every triangle is counted once.
+func ConcurrentTriangleCount(G *Core.Network, concurrentCount int) int64 {
+	retVal, _ := ConcurrentTriangleCountContext(context.Background(), G, concurrentCount, nil)
+	return retVal
+}
+
+// As ConcurrentTriangleCount, but stops the goroutines and returns the context's error if ctx is cancelled, and reports progress, if not nil,
+// as each partition finishes
+func ConcurrentTriangleCountContext(ctx context.Context, G *Core.Network, concurrentCount int, progress ProgressFunc) (int64, error) {
+	return countTriangles(ctx, G, concurrentCount, newProgressReporter("ConcurrentTriangleCountContext", progress))
+}
+
+func countTriangles(ctx context.Context, G *Core.Network, concurrentCount int, reporter *progressReporter) (int64, error) {
+	counts, err := runPartitioned(ctx, G, concurrentCount, reporter, func(v uint32) float64 {
+		count := 0
+		higher := make(map[uint32]bool)
+		for u := range undirectedNeighbors(G, v) {
+			if u > v {
+				higher[u] = true
+			}
+		}
+		for u := range higher {
+			for w := range undirectedNeighbors(G, u) {
+				if w > u && higher[w] {
+					count++
+				}
+			}
+		}
+		return float64(count)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var retVal int64 = 0
+	for _, count := range counts {
+		retVal += int64(count)
+	}
+	return retVal, nil
+}
+
+func localClustering(G *Core.Network, vertex uint32, variant ClusteringVariant, maxWeight float64) float64 {
+	neighbors := undirectedNeighbors(G, vertex)
+	k := float64(len(neighbors))
+	// with every weight zero, the weighted variants have no triangle intensity to measure
+	if k < 2 || (variant == Onnela && maxWeight == 0) {
+		return 0.0
+	}
+
+	strength := 0.0
+	for _, wt := range neighbors {
+		strength += wt
+	}
+
+	sum := 0.0
+	for j, wij := range neighbors {
+		for h, wjh := range undirectedNeighbors(G, j) {
+			if j >= h {
+				continue
+			}
+			wih, ok := neighbors[h]
+			if !ok {
+				continue
+			}
+			switch variant {
+			case Barrat:
+				sum += wij + wih
+			case Onnela:
+				sum += 2 * math.Cbrt((wij/maxWeight)*(wih/maxWeight)*(wjh/maxWeight))
+			default:
+				sum += 2
+			}
+		}
+	}
+
+	if variant == Barrat {
+		if strength == 0 {
+			return 0.0
+		}
+		return sum / (strength * (k - 1))
+	}
+	return sum / (k * (k - 1))
+}
+
+// neighbors in either direction, with the weights of reciprocal directed edges averaged
+func undirectedNeighbors(G *Core.Network, vertex uint32) map[uint32]float64 {
+	neighbors := G.GetNeighbors(vertex)
+	retVal := make(map[uint32]float64, len(neighbors))
+	for n, wt := range neighbors {
+		retVal[n] = float64(wt)
+	}
+	if G.Directed() {
+		for n, wt := range G.GetSources(vertex) {
+			out, ok := retVal[n]
+			if ok {
+				retVal[n] = (out + float64(wt)) / 2
+			} else {
+				retVal[n] = float64(wt)
+			}
+		}
+	}
+	return retVal
+}
+
+// the greatest edge weight, or the context's error if ctx is cancelled during the scan
+func maxUndirectedWeight(ctx context.Context, G *Core.Network) (float64, error) {
+	retVal := 0.0
+	for _, v := range G.Vertices(false) {
+		if cancelled(ctx) {
+			return 0.0, ctx.Err()
+		}
+		for _, wt := range undirectedNeighbors(G, v) {
+			if wt > retVal {
+				retVal = wt
+			}
+		}
+	}
+	return retVal, nil
+}
+
+// sum work over each of the contiguous partitions of the sorted vertices concurrently, collecting one result per partition; the goroutines
+// stop early if ctx is cancelled
+func runPartitioned(ctx context.Context, G *Core.Network, concurrentCount int, reporter *progressReporter, work func(uint32) float64) ([]float64, error) {
+	vertices := G.Vertices(true)
+	if len(vertices) == 0 {
+		return []float64{}, ctx.Err()
+	}
+	if concurrentCount > len(vertices) {
+		concurrentCount = len(vertices)
+	}
+	if concurrentCount < 1 {
+		concurrentCount = 1
+	}
+
+	partitions, _ := ContiguousPartitions(vertices, concurrentCount)
+	resultChannel := make(chan partitionResult, concurrentCount)
+	for idx, partition := range partitions {
+		go func(idx int, partition []uint32) {
+			sum := 0.0
+			for _, v := range partition {
+				if cancelled(ctx) {
+					break
+				}
+				sum += work(v)
+			}
+			if !cancelled(ctx) {
+				reporter.emit(1, idx)
+			}
+			resultChannel <- partitionResult{partition: idx, value: sum}
+		}(idx, partition)
+	}
+
+	// keep results in partition order so sums do not depend on which goroutine finishes first
+	retVal := make([]float64, concurrentCount)
+	for i := 0; i < concurrentCount; i++ {
+		result := <-resultChannel
+		retVal[result.partition] = result.value
+	}
+	close(resultChannel)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return retVal, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestClusteringComplete(t *testing.T) {
+	G := Core.NewNetwork(false)
+	for i := 0; i < 5; i++ {
+		for k := i + 1; k < 5; k++ {
+			_ = G.AddEdge(uint32(i), uint32(k), 2.0)
+		}
+	}
+
+	if ConcurrentTriangleCount(G, 2) != 10 {
+		t.Errorf("Expected 10 triangles in K5, found %d", ConcurrentTriangleCount(G, 2))
+	}
+	if Triangles(G, 0) != 6 {
+		t.Errorf("Expected 6 triangles at a vertex of K5, found %d", Triangles(G, 0))
+	}
+	for _, variant := range []ClusteringVariant{Unweighted, Barrat, Onnela} {
+		c := AverageClustering(G, variant, 3)
+		if math.Abs(c-1.0) > 1e-9 {
+			t.Errorf("Expected clustering 1 for K5 with variant %d, found %f", variant, c)
+		}
+	}
+	if math.Abs(Transitivity(G, 2)-1.0) > 1e-9 {
+		t.Errorf("Expected transitivity 1 for K5, found %f", Transitivity(G, 2))
+	}
+}
+
+func TestClusteringSimple(t *testing.T) {
+	// a triangle 1-2-3 with a pendant 4 on 3
+	G := Core.NewNetwork(true)
+	_ = G.AddEdge(1, 2, 1.0)
+	_ = G.AddEdge(2, 3, 1.0)
+	_ = G.AddEdge(3, 1, 1.0)
+	_ = G.AddEdge(3, 4, 3.0)
+
+	if c := LocalClustering(G, 3, Unweighted); math.Abs(c-1.0/3.0) > 1e-9 {
+		t.Errorf("Expected clustering 1/3 at vertex 3, found %f", c)
+	}
+	// Barrat: (w31 + w32) / (s3 (k3 - 1)) = 2 / (5 * 2)
+	if c := LocalClustering(G, 3, Barrat); math.Abs(c-0.2) > 1e-9 {
+		t.Errorf("Expected Barrat clustering 0.2 at vertex 3, found %f", c)
+	}
+	// Onnela: the triangle's weights normalized by the maximum of 3 give a geometric mean of 1/3, counted for both orderings, over k3 (k3 - 1)
+	if c := LocalClustering(G, 3, Onnela); math.Abs(c-(2.0/3.0)/6.0) > 1e-9 {
+		t.Errorf("Expected Onnela clustering 1/9 at vertex 3, found %f", c)
+	}
+	// 1 triangle, triples: 1 + 1 + 3 + 0
+	if tr := Transitivity(G, 1); math.Abs(tr-0.6) > 1e-9 {
+		t.Errorf("Expected transitivity 0.6, found %f", tr)
+	}
+}
+
+func TestConcurrentTriangleCount(t *testing.T) {
+	G, _ := Generators.ErdosRenyiGnp(300, 0.05, false, rand.New(rand.NewSource(1)))
+	sum := 0
+	for _, v := range G.Vertices(false) {
+		sum += Triangles(G, v)
+	}
+	for _, count := range []int{1, 3, 8} {
+		if ConcurrentTriangleCount(G, count) != int64(sum/3) {
+			t.Errorf("Concurrent count with %d goroutines disagrees with the per-vertex count", count)
+		}
+	}
+}
+
+func TestClusteringZeroWeights(t *testing.T) {
+	// K4 with every edge weight zero
+	G := Core.NewNetwork(false)
+	for i := uint32(0); i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			_ = G.AddEdge(i, j, 0.0)
+		}
+	}
+	for _, variant := range []ClusteringVariant{Barrat, Onnela} {
+		if c := LocalClustering(G, 0, variant); c != 0.0 {
+			t.Errorf("Expected clustering 0 with zero weights for variant %d, found %f", variant, c)
+		}
+		if c := AverageClustering(G, variant, 2); c != 0.0 {
+			t.Errorf("Expected average clustering 0 with zero weights for variant %d, found %f", variant, c)
+		}
+	}
+	if c := LocalClustering(G, 0, Unweighted); math.Abs(c-1.0) > 1e-9 {
+		t.Errorf("Expected unweighted clustering 1 for K4, found %f", c)
+	}
+}
@@ -0,0 +1,336 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Vertex coloring: greedy coloring in several vertex orders, DSATUR (Brelaz, New methods to color the vertices of a graph, CACM 22(4), 1979),
+// and a concurrent Jones-Plassmann coloring (Jones and Plassmann, A parallel graph coloring heuristic, SIAM J. Sci. Comput. 14(3), 1993)
+// Colors are numbered from 0 and edge direction is ignored.
+
+package Algorithms
+
+import (
+	"container/heap"
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"sort"
+)
+
+// the order in which greedy coloring visits the vertices
+type ColoringOrder int
+
+const (
+	NaturalOrder ColoringOrder = iota // ascending vertex id
+	LargestFirst                      // descending degree, after Welsh and Powell
+	SmallestLast                      // reverse of repeatedly removing a vertex of minimum remaining degree, after Matula and Beck
+)
+
+type vertexColor struct {
+	vertex uint32
+	color  int
+}
+
+// Colors each vertex in the given order with the smallest color not used by its neighbors
+func GreedyColoring(G *Core.Network, order ColoringOrder) (map[uint32]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+
+	neighbors := coloringNeighbors(G)
+	vertices := G.Vertices(true)
+	switch order {
+	case LargestFirst:
+		sort.SliceStable(vertices, func(i, j int) bool { return len(neighbors[vertices[i]]) > len(neighbors[vertices[j]]) })
+	case SmallestLast:
+		vertices = smallestLastOrder(vertices, neighbors)
+	}
+
+	retVal := make(map[uint32]int, len(vertices))
+	for _, v := range vertices {
+		retVal[v] = smallestFreeColor(neighbors[v], func(n uint32) (int, bool) {
+			c, ok := retVal[n]
+			return c, ok
+		})
+	}
+	return retVal, nil
+}
+
+// Colors vertices one at a time, always choosing the uncolored vertex whose neighbors already use the most distinct colors, ties going
+// to the higher degree and then the lower id
+func DSATURColoring(G *Core.Network) (map[uint32]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+
+	neighbors := coloringNeighbors(G)
+	saturation := make(map[uint32]map[int]bool, len(neighbors))
+	queue := &saturationHeap{}
+	for _, v := range G.Vertices(false) {
+		saturation[v] = make(map[int]bool)
+		heap.Push(queue, saturationEntry{vertex: v, degree: len(neighbors[v])})
+	}
+
+	retVal := make(map[uint32]int, len(neighbors))
+	for queue.Len() > 0 {
+		entry := heap.Pop(queue).(saturationEntry)
+		if _, done := retVal[entry.vertex]; done || entry.saturation != len(saturation[entry.vertex]) {
+			continue
+		}
+		color := smallestFreeColor(neighbors[entry.vertex], func(n uint32) (int, bool) {
+			c, ok := retVal[n]
+			return c, ok
+		})
+		retVal[entry.vertex] = color
+		for _, n := range neighbors[entry.vertex] {
+			if _, done := retVal[n]; !done && !saturation[n][color] {
+				saturation[n][color] = true
+				heap.Push(queue, saturationEntry{vertex: n, saturation: len(saturation[n]), degree: len(neighbors[n])})
+			}
+		}
+	}
+	return retVal, nil
+}
+
+// Jones-Plassmann coloring using routineCount goroutines.  Each vertex receives a random priority from seed, and in each round every
+// vertex whose higher priority neighbors are all colored takes the smallest color they leave free.  Such vertices are never adjacent, so
+// a round is colored concurrently; the result depends on seed but not on routineCount.
+func ConcurrentJonesPlassmann(G *Core.Network, routineCount int, seed int64) (map[uint32]int, error) {
+	return ConcurrentJonesPlassmannContext(context.Background(), G, routineCount, seed, nil)
+}
+
+// As ConcurrentJonesPlassmann, but stops the goroutines and returns the context's error if ctx is cancelled, and reports progress, if not nil,
+// after each round
+func ConcurrentJonesPlassmannContext(ctx context.Context, G *Core.Network, routineCount int, seed int64, progress ProgressFunc) (map[uint32]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if routineCount < 1 {
+		routineCount = 1
+	}
+
+	neighbors := coloringNeighbors(G)
+	vertices := G.Vertices(true)
+	r := rand.New(rand.NewSource(seed))
+	priority := make(map[uint32]int, len(vertices))
+	for i, p := range r.Perm(len(vertices)) {
+		priority[vertices[i]] = p
+	}
+
+	// waiting counts the uncolored neighbors of higher priority
+	waiting := make(map[uint32]int, len(vertices))
+	ready := make([]uint32, 0)
+	for _, v := range vertices {
+		for _, n := range neighbors[v] {
+			if priority[n] > priority[v] {
+				waiting[v]++
+			}
+		}
+		if waiting[v] == 0 {
+			ready = append(ready, v)
+		}
+	}
+
+	// colors is written only between rounds, while the goroutines wait for work, so they may read it freely during a round
+	colors := make(map[uint32]int, len(vertices))
+	results := make(chan []vertexColor, routineCount)
+	assignments := make([]chan []uint32, routineCount)
+	for i := range assignments {
+		assignments[i] = make(chan []uint32, 1)
+		go serviceColorings(neighbors, colors, assignments[i], results)
+	}
+	defer func() {
+		for _, assignment := range assignments {
+			close(assignment)
+		}
+	}()
+
+	reporter := newProgressReporter("ConcurrentJonesPlassmannContext", progress)
+	for round := 1; len(ready) > 0; round++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// late rounds may have fewer ready vertices than goroutines; only that many receive work, each at least one vertex
+		count := routineCount
+		if count > len(ready) {
+			count = len(ready)
+		}
+		partitions, _ := ContiguousPartitions(ready, count)
+		for i, partition := range partitions {
+			assignments[i] <- partition
+		}
+		colored := make([]vertexColor, 0, len(ready))
+		for range partitions {
+			colored = append(colored, <-results...)
+		}
+
+		for _, c := range colored {
+			colors[c.vertex] = c.color
+		}
+		next := make([]uint32, 0)
+		for _, c := range colored {
+			for _, n := range neighbors[c.vertex] {
+				if priority[n] < priority[c.vertex] {
+					waiting[n]--
+					if waiting[n] == 0 {
+						next = append(next, n)
+					}
+				}
+			}
+		}
+		sort.Slice(next, func(i, j int) bool { return next[i] < next[j] })
+		ready = next
+		reporter.emit(round, -1)
+	}
+	return colors, nil
+}
+
+// goroutine colors each batch of vertices from the colors of their neighbors and sends the batch back to main
+func serviceColorings(neighbors map[uint32][]uint32, colors map[uint32]int, assignments <-chan []uint32, results chan<- []vertexColor) {
+	for batch := range assignments {
+		retVal := make([]vertexColor, len(batch))
+		for i, v := range batch {
+			retVal[i] = vertexColor{vertex: v, color: smallestFreeColor(neighbors[v], func(n uint32) (int, bool) {
+				c, ok := colors[n]
+				return c, ok
+			})}
+		}
+		results <- retVal
+	}
+}
+
+// Returns an error naming an uncolored vertex, a negative color, or an edge whose endpoints share a color
+func ValidateColoring(G *Core.Network, colors map[uint32]int) error {
+	if G == nil {
+		return Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	for _, v := range G.Vertices(true) {
+		c, ok := colors[v]
+		if !ok {
+			return Core.NewNetworkArgumentError(Sprintf("Vertex %d is not colored", v))
+		}
+		if c < 0 {
+			return Core.NewNetworkArgumentError(Sprintf("Vertex %d has negative color %d", v, c))
+		}
+		for _, edge := range traversalNeighbors(G, v, Outgoing) {
+			if colors[edge.to] == c {
+				return Core.NewNetworkArgumentError(Sprintf("Adjacent vertices %d and %d share color %d", v, edge.to, c))
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the number of distinct colors used
+func ColorCount(colors map[uint32]int) int {
+	distinct := make(map[int]bool)
+	for _, c := range colors {
+		distinct[c] = true
+	}
+	return len(distinct)
+}
+
+// sorted neighbors of every vertex ignoring direction
+func coloringNeighbors(G *Core.Network) map[uint32][]uint32 {
+	retVal := make(map[uint32][]uint32, G.Order())
+	for _, v := range G.Vertices(false) {
+		edges := traversalNeighbors(G, v, AnyDirection)
+		retVal[v] = make([]uint32, len(edges))
+		for i, edge := range edges {
+			retVal[v][i] = edge.to
+		}
+	}
+	return retVal
+}
+
+// the smallest color not used by any colored neighbor
+func smallestFreeColor(neighbors []uint32, colorOf func(uint32) (int, bool)) int {
+	used := make([]bool, len(neighbors)+1)
+	for _, n := range neighbors {
+		if c, ok := colorOf(n); ok && c < len(used) {
+			used[c] = true
+		}
+	}
+	for c := range used {
+		if !used[c] {
+			return c
+		}
+	}
+	return len(used)
+}
+
+// repeatedly removes a vertex of minimum remaining degree (lowest id on ties); the coloring order is the reverse of removal
+func smallestLastOrder(vertices []uint32, neighbors map[uint32][]uint32) []uint32 {
+	degree := make(map[uint32]int, len(vertices))
+	queue := &saturationHeap{smallest: true}
+	for _, v := range vertices {
+		degree[v] = len(neighbors[v])
+		heap.Push(queue, saturationEntry{vertex: v, degree: degree[v]})
+	}
+	removed := make(map[uint32]bool, len(vertices))
+	retVal := make([]uint32, len(vertices))
+	for i := len(vertices) - 1; i >= 0; {
+		entry := heap.Pop(queue).(saturationEntry)
+		if removed[entry.vertex] || entry.degree != degree[entry.vertex] {
+			continue
+		}
+		removed[entry.vertex] = true
+		retVal[i] = entry.vertex
+		i--
+		for _, n := range neighbors[entry.vertex] {
+			if !removed[n] {
+				degree[n]--
+				heap.Push(queue, saturationEntry{vertex: n, degree: degree[n]})
+			}
+		}
+	}
+	return retVal
+}
+
+type saturationEntry struct {
+	vertex     uint32
+	saturation int
+	degree     int
+}
+
+// max-heap on saturation then degree, ties to the lower id; with smallest set, a min-heap on degree
+type saturationHeap struct {
+	entries  []saturationEntry
+	smallest bool
+}
+
+func (h *saturationHeap) Len() int { return len(h.entries) }
+func (h *saturationHeap) Less(i, j int) bool {
+	a, b := h.entries[i], h.entries[j]
+	if a.saturation != b.saturation {
+		return a.saturation > b.saturation
+	}
+	if a.degree != b.degree {
+		return (a.degree > b.degree) != h.smallest
+	}
+	return a.vertex < b.vertex
+}
+func (h *saturationHeap) Swap(i, j int)      { h.entries[i], h.entries[j] = h.entries[j], h.entries[i] }
+func (h *saturationHeap) Push(x interface{}) { h.entries = append(h.entries, x.(saturationEntry)) }
+func (h *saturationHeap) Pop() interface{} {
+	last := h.entries[len(h.entries)-1]
+	h.entries = h.entries[:len(h.entries)-1]
+	return last
+}
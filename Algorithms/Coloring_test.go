@@ -0,0 +1,125 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math/rand"
+	"testing"
+)
+
+func TestGreedyColoring(t *testing.T) {
+	// a crown graph: 1-4 joined to 5-8 except i to i+4; natural order needs 4 colors, largest first and DSATUR 2
+	G := Core.NewNetwork(false)
+	for i := uint32(1); i <= 4; i++ {
+		for k := uint32(5); k <= 8; k++ {
+			if k != i+4 {
+				_ = G.AddEdge(i, k, 1.0)
+			}
+		}
+	}
+	// interleave the natural order so greedy coloring goes wrong: 1, 5 then 2, 6 ...
+	H := Core.NewNetwork(false)
+	relabel := map[uint32]uint32{1: 1, 5: 2, 2: 3, 6: 4, 3: 5, 7: 6, 4: 7, 8: 8}
+	for _, v := range G.Vertices(false) {
+		for n := range G.GetNeighbors(v) {
+			if v < n {
+				_ = H.AddEdge(relabel[v], relabel[n], 1.0)
+			}
+		}
+	}
+
+	colors, err := GreedyColoring(H, NaturalOrder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ValidateColoring(H, colors); err != nil {
+		t.Error(err)
+	}
+	if ColorCount(colors) != 4 {
+		t.Errorf("Expected natural order to use 4 colors on the crown graph, found %d", ColorCount(colors))
+	}
+	colors, _ = DSATURColoring(H)
+	if err = ValidateColoring(H, colors); err != nil || ColorCount(colors) != 2 {
+		t.Errorf("Expected DSATUR to 2-color the crown graph, found %d colors (%v)", ColorCount(colors), err)
+	}
+
+	r := rand.New(rand.NewSource(40))
+	for trial := 0; trial < 5; trial++ {
+		N, _ := Generators.ErdosRenyiGnp(200, 0.05, trial%2 == 0, r)
+		for _, order := range []ColoringOrder{NaturalOrder, LargestFirst, SmallestLast} {
+			colors, _ := GreedyColoring(N, order)
+			if err := ValidateColoring(N, colors); err != nil {
+				t.Errorf("Greedy coloring with order %d: %v", order, err)
+			}
+		}
+		colors, _ := DSATURColoring(N)
+		if err := ValidateColoring(N, colors); err != nil {
+			t.Errorf("DSATUR: %v", err)
+		}
+	}
+
+	monochrome := make(map[uint32]int)
+	for _, v := range H.Vertices(false) {
+		monochrome[v] = 0
+	}
+	if ValidateColoring(H, monochrome) == nil {
+		t.Errorf("Expected an invalid coloring to be rejected")
+	}
+	if ValidateColoring(H, map[uint32]int{}) == nil {
+		t.Errorf("Expected an incomplete coloring to be rejected")
+	}
+}
+
+func TestSmallestLastWheel(t *testing.T) {
+	// smallest-last colors a wheel, whose degeneracy is 3, with at most 4 colors
+	G := Core.NewNetwork(false)
+	for i := uint32(1); i <= 9; i++ {
+		_ = G.AddEdge(0, i, 1.0)
+		_ = G.AddEdge(i, i%9+1, 1.0)
+	}
+	colors, _ := GreedyColoring(G, SmallestLast)
+	if err := ValidateColoring(G, colors); err != nil || ColorCount(colors) > 4 {
+		t.Errorf("Expected at most 4 colors for a wheel, found %d (%v)", ColorCount(colors), err)
+	}
+}
+
+func TestConcurrentJonesPlassmann(t *testing.T) {
+	r := rand.New(rand.NewSource(41))
+	N, _ := Generators.ErdosRenyiGnp(500, 0.02, false, r)
+	first, err := ConcurrentJonesPlassmann(N, 1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ValidateColoring(N, first); err != nil {
+		t.Error(err)
+	}
+	for _, count := range []int{2, 4, 7, 600} {
+		colors, _ := ConcurrentJonesPlassmann(N, count, 7)
+		for v, c := range first {
+			if colors[v] != c {
+				t.Fatalf("Coloring with %d goroutines differs from the coloring with one at vertex %d", count, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Core and truss decompositions
+// Core numbers follow Batagelj and Zaversnik, An O(m) algorithm for cores decomposition of networks, 2003.  Truss numbers are found by
+// peeling edges in order of triangle support, after Cohen, Trusses: cohesive subgraphs for social network analysis, 2008.
+
+package Algorithms
+
+import (
+	"container/heap"
+	"context"
+	"github.com/smohr1824/Networks/Core"
+)
+
+// which degree defines the cores of a directed network; undirected networks have a single degree
+type DegreeType int
+
+const (
+	TotalDegree DegreeType = iota
+	InDegree
+	OutDegree
+)
+
+// Returns the core number of every vertex: the largest k such that the vertex belongs to a subgraph in which every vertex has degree at least k.
+// Total degree counts in-edges and out-edges separately, so a reciprocal pair contributes two.
+func CoreNumbers(G *Core.Network, degreeType DegreeType) (map[uint32]int, error) {
+	return CoreNumbersContext(context.Background(), G, degreeType, nil)
+}
+
+// As CoreNumbers, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as peeling reaches each higher core
+func CoreNumbersContext(ctx context.Context, G *Core.Network, degreeType DegreeType, progress ProgressFunc) (map[uint32]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if !G.Directed() {
+		degreeType = TotalDegree
+	}
+
+	vertices := G.Vertices(true)
+	index := make(map[uint32]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+
+	// removing a vertex lowers the degree of each vertex in affected once per edge joining them
+	degree := make([]int, len(vertices))
+	affected := make([][]int, len(vertices))
+	maxDegree := 0
+	for i, v := range vertices {
+		switch {
+		case !G.Directed():
+			degree[i] = G.Degree(v)
+		case degreeType == InDegree:
+			degree[i] = G.InDegree(v)
+		case degreeType == OutDegree:
+			degree[i] = G.OutDegree(v)
+		default:
+			degree[i] = G.InDegree(v) + G.OutDegree(v)
+		}
+		if degree[i] > maxDegree {
+			maxDegree = degree[i]
+		}
+
+		if !G.Directed() || degreeType != OutDegree {
+			for n := range G.GetNeighbors(v) {
+				affected[i] = append(affected[i], index[n])
+			}
+		}
+		if G.Directed() && degreeType != InDegree {
+			for n := range G.GetSources(v) {
+				affected[i] = append(affected[i], index[n])
+			}
+		}
+	}
+
+	// bin sort the vertices by degree; pos and order are kept consistent as degrees drop
+	bin := make([]int, maxDegree+1)
+	for _, d := range degree {
+		bin[d]++
+	}
+	start := 0
+	for d := range bin {
+		count := bin[d]
+		bin[d] = start
+		start += count
+	}
+	pos := make([]int, len(vertices))
+	order := make([]int, len(vertices))
+	for i, d := range degree {
+		pos[i] = bin[d]
+		order[pos[i]] = i
+		bin[d]++
+	}
+	for d := maxDegree; d > 0; d-- {
+		bin[d] = bin[d-1]
+	}
+	bin[0] = 0
+
+	reporter := newProgressReporter("CoreNumbersContext", progress)
+	for i, core := 0, 0; i < len(order); i++ {
+		if cancelled(ctx) {
+			return nil, ctx.Err()
+		}
+		v := order[i]
+		if degree[v] > core {
+			core = degree[v]
+			reporter.emit(core, -1)
+		}
+		for _, u := range affected[v] {
+			if degree[u] > degree[v] {
+				// swap u with the first vertex of its bin, then shrink the bin
+				du := degree[u]
+				pu := pos[u]
+				pw := bin[du]
+				w := order[pw]
+				if u != w {
+					pos[u], pos[w] = pw, pu
+					order[pu], order[pw] = w, u
+				}
+				bin[du]++
+				degree[u]--
+			}
+		}
+	}
+
+	retVal := make(map[uint32]int, len(vertices))
+	for i, v := range vertices {
+		retVal[v] = degree[i]
+	}
+	return retVal, nil
+}
+
+// Returns the subgraph induced by the vertices with core number at least k
+func KCore(G *Core.Network, k int, degreeType DegreeType) (*Core.Network, error) {
+	return coreSubgraph(G, degreeType, func(c int) bool { return c >= k })
+}
+
+// Returns the subgraph induced by the vertices with core number exactly k
+func KShell(G *Core.Network, k int, degreeType DegreeType) (*Core.Network, error) {
+	return coreSubgraph(G, degreeType, func(c int) bool { return c == k })
+}
+
+// Returns the subgraph induced by the vertices with core number at most k, i.e., the network with its (k+1)-core removed
+func KCrust(G *Core.Network, k int, degreeType DegreeType) (*Core.Network, error) {
+	return coreSubgraph(G, degreeType, func(c int) bool { return c <= k })
+}
+
+func coreSubgraph(G *Core.Network, degreeType DegreeType, keep func(int) bool) (*Core.Network, error) {
+	cores, err := CoreNumbers(G, degreeType)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[uint32]bool)
+	for v, c := range cores {
+		if keep(c) {
+			kept[v] = true
+		}
+	}
+	return inducedSubgraph(G, kept), nil
+}
+
+// Returns the truss number of every edge, keyed by the lower then the higher vertex id: the largest k such that the edge belongs to a
+// subgraph in which every edge lies on at least k - 2 triangles.  Directed networks are treated as undirected.
+func TrussNumbers(G *Core.Network) (map[uint32]map[uint32]int, error) {
+	return TrussNumbersContext(context.Background(), G, nil)
+}
+
+// As TrussNumbers, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as peeling reaches each higher truss
+func TrussNumbersContext(ctx context.Context, G *Core.Network, progress ProgressFunc) (map[uint32]map[uint32]int, error) {
+	return trussNumbers(ctx, G, newProgressReporter("TrussNumbersContext", progress))
+}
+
+func trussNumbers(ctx context.Context, G *Core.Network, reporter *progressReporter) (map[uint32]map[uint32]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+
+	adjacent := make(map[uint32]map[uint32]bool, G.Order())
+	for _, v := range G.Vertices(false) {
+		adjacent[v] = make(map[uint32]bool)
+		for n := range undirectedNeighbors(G, v) {
+			adjacent[v][n] = true
+		}
+	}
+
+	support := make(map[uint32]map[uint32]int)
+	queue := &supportHeap{}
+	for u, neighbors := range adjacent {
+		support[u] = make(map[uint32]int)
+		for v := range neighbors {
+			if u < v {
+				s := 0
+				for w := range neighbors {
+					if adjacent[v][w] {
+						s++
+					}
+				}
+				support[u][v] = s
+				heap.Push(queue, supportEntry{from: u, to: v, support: s})
+			}
+		}
+	}
+
+	retVal := make(map[uint32]map[uint32]int)
+	level := 2
+	for queue.Len() > 0 {
+		if cancelled(ctx) {
+			return nil, ctx.Err()
+		}
+		entry := heap.Pop(queue).(supportEntry)
+		u, v := entry.from, entry.to
+		if !adjacent[u][v] || support[u][v] != entry.support {
+			continue
+		}
+		if entry.support+2 > level {
+			level = entry.support + 2
+			reporter.emit(level, -1)
+		}
+		if _, ok := retVal[u]; !ok {
+			retVal[u] = make(map[uint32]int)
+		}
+		retVal[u][v] = level
+
+		// removing the edge breaks the triangles it closes
+		for w := range adjacent[u] {
+			if w != v && adjacent[v][w] {
+				for _, x := range []uint32{u, v} {
+					a, b := x, w
+					if a > b {
+						a, b = b, a
+					}
+					support[a][b]--
+					heap.Push(queue, supportEntry{from: a, to: b, support: support[a][b]})
+				}
+			}
+		}
+		delete(adjacent[u], v)
+		delete(adjacent[v], u)
+	}
+	return retVal, nil
+}
+
+// Returns the subgraph formed by the edges with truss number at least k and the vertices they join
+func KTruss(G *Core.Network, k int) (*Core.Network, error) {
+	return KTrussContext(context.Background(), G, k, nil)
+}
+
+// As KTruss, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as TrussNumbersContext does
+func KTrussContext(ctx context.Context, G *Core.Network, k int, progress ProgressFunc) (*Core.Network, error) {
+	truss, err := trussNumbers(ctx, G, newProgressReporter("KTrussContext", progress))
+	if err != nil {
+		return nil, err
+	}
+	retVal := Core.NewNetwork(G.Directed())
+	for u, targets := range truss {
+		for v, t := range targets {
+			if t < k {
+				continue
+			}
+			retVal.AddVertex(u)
+			retVal.AddVertex(v)
+			// keep both directions of a reciprocal pair in a directed network
+			if G.HasEdge(u, v) {
+				_ = retVal.AddEdge(u, v, G.EdgeWeight(u, v))
+			}
+			if G.Directed() && G.HasEdge(v, u) {
+				_ = retVal.AddEdge(v, u, G.EdgeWeight(v, u))
+			}
+		}
+	}
+	return retVal, nil
+}
+
+// the subgraph of G induced by the given vertices, keeping edge weights and direction
+func inducedSubgraph(G *Core.Network, vertices map[uint32]bool) *Core.Network {
+	retVal := Core.NewNetwork(G.Directed())
+	for v := range vertices {
+		retVal.AddVertex(v)
+	}
+	for v := range vertices {
+		for n, wt := range G.GetNeighbors(v) {
+			if vertices[n] && (G.Directed() || v < n) {
+				_ = retVal.AddEdge(v, n, wt)
+			}
+		}
+	}
+	return retVal
+}
+
+type supportEntry struct {
+	from    uint32
+	to      uint32
+	support int
+}
+
+// min-heap on support, ties broken by endpoints
+type supportHeap []supportEntry
+
+func (h supportHeap) Len() int { return len(h) }
+func (h supportHeap) Less(i, j int) bool {
+	if h[i].support != h[j].support {
+		return h[i].support < h[j].support
+	}
+	if h[i].from != h[j].from {
+		return h[i].from < h[j].from
+	}
+	return h[i].to < h[j].to
+}
+func (h supportHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *supportHeap) Push(x interface{}) { *h = append(*h, x.(supportEntry)) }
+func (h *supportHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,134 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"testing"
+)
+
+// core numbers by repeatedly deleting every vertex of degree below k
+func bruteCores(G *Core.Network, degreeType DegreeType) map[uint32]int {
+	retVal := make(map[uint32]int)
+	for k := 0; ; k++ {
+		H := G.Clone()
+		for changed := true; changed; {
+			changed = false
+			for _, v := range H.Vertices(false) {
+				d := H.Degree(v)
+				if H.Directed() && degreeType == InDegree {
+					d = H.InDegree(v)
+				} else if H.Directed() && degreeType == OutDegree {
+					d = H.OutDegree(v)
+				}
+				if d < k {
+					H.RemoveVertex(v)
+					changed = true
+				}
+			}
+		}
+		if H.Order() == 0 {
+			return retVal
+		}
+		for _, v := range H.Vertices(false) {
+			retVal[v] = k
+		}
+	}
+}
+
+func TestCoreNumbers(t *testing.T) {
+	// K4 on 1-4 with a path 4-5-6
+	G := Core.NewNetwork(false)
+	for i := uint32(1); i <= 4; i++ {
+		for k := i + 1; k <= 4; k++ {
+			_ = G.AddEdge(i, k, 1.0)
+		}
+	}
+	_ = G.AddEdge(4, 5, 1.0)
+	_ = G.AddEdge(5, 6, 1.0)
+	cores, err := CoreNumbers(G, TotalDegree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cores[1] != 3 || cores[4] != 3 || cores[5] != 1 || cores[6] != 1 {
+		t.Errorf("Expected core numbers 3 for K4 and 1 for the path, found %v", cores)
+	}
+
+	C, _ := KCore(G, 3, TotalDegree)
+	if C.Order() != 4 || C.Size() != 6 {
+		t.Errorf("Expected the 3-core to be K4, found order %d size %d", C.Order(), C.Size())
+	}
+	S, _ := KShell(G, 1, TotalDegree)
+	if S.Order() != 2 || S.Size() != 1 {
+		t.Errorf("Expected the 1-shell to be the edge 5-6, found order %d size %d", S.Order(), S.Size())
+	}
+	R, _ := KCrust(G, 2, TotalDegree)
+	if R.Order() != 2 {
+		t.Errorf("Expected the 2-crust to hold 2 vertices, found %d", R.Order())
+	}
+
+	r := rand.New(rand.NewSource(36))
+	for trial := 0; trial < 10; trial++ {
+		H := randomWeighted(25, 0.2, trial%2 == 0, r)
+		for _, degreeType := range []DegreeType{TotalDegree, InDegree, OutDegree} {
+			cores, _ := CoreNumbers(H, degreeType)
+			expected := bruteCores(H, degreeType)
+			for v, c := range expected {
+				if cores[v] != c {
+					t.Errorf("Vertex %d: expected core number %d for degree type %d, found %d", v, c, degreeType, cores[v])
+				}
+			}
+		}
+	}
+}
+
+func TestTruss(t *testing.T) {
+	// K4 on 1-4, a triangle 4-5-6 and a pendant 6-7
+	G := Core.NewNetwork(false)
+	for i := uint32(1); i <= 4; i++ {
+		for k := i + 1; k <= 4; k++ {
+			_ = G.AddEdge(i, k, 1.0)
+		}
+	}
+	_ = G.AddEdge(4, 5, 1.0)
+	_ = G.AddEdge(5, 6, 1.0)
+	_ = G.AddEdge(6, 4, 1.0)
+	_ = G.AddEdge(6, 7, 1.0)
+
+	truss, err := TrussNumbers(G)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if truss[1][2] != 4 || truss[3][4] != 4 || truss[4][5] != 3 || truss[4][6] != 3 || truss[6][7] != 2 {
+		t.Errorf("Unexpected truss numbers %v", truss)
+	}
+
+	T, _ := KTruss(G, 3)
+	if T.Order() != 6 || T.Size() != 9 {
+		t.Errorf("Expected the 3-truss to have order 6 and size 9, found %d and %d", T.Order(), T.Size())
+	}
+	T, _ = KTruss(G, 4)
+	if T.Order() != 4 || T.Size() != 6 {
+		t.Errorf("Expected the 4-truss to be K4, found order %d and size %d", T.Order(), T.Size())
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// edge type shared by algorithms that return sets of edges
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+)
+
+type Edge struct {
+	From   uint32
+	To     uint32
+	Weight float32
+}
+
+// the edge between u and v ignoring direction, with From < To; for a reciprocal pair in a directed network the weight is that of From -> To
+func undirectedEdge(G *Core.Network, u uint32, v uint32) Edge {
+	if u > v {
+		u, v = v, u
+	}
+	if G.Directed() && !G.HasEdge(u, v) {
+		return Edge{From: u, To: v, Weight: G.EdgeWeight(v, u)}
+	}
+	return Edge{From: u, To: v, Weight: G.EdgeWeight(u, v)}
+}
+
+func sortEdges(edges []Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// errors specific to the algorithms package
+
+package Algorithms
+
+import (
+	. "fmt"
+)
+
+// returned when an algorithm requiring an acyclic network finds a cycle; Cycle lists its vertices in order, first vertex not repeated
+type CycleError struct {
+	message string
+	Cycle   []uint32
+}
+
+func NewCycleError(message string, cycle []uint32) *CycleError {
+	return &CycleError{
+		message: message,
+		Cycle:   cycle,
+	}
+}
+
+func (e *CycleError) Error() string {
+	return Sprintf("%s: %v", e.message, e.Cycle)
+}
@@ -0,0 +1,365 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Maximum flow and minimum cuts treating edge weights as capacities
+// MaxFlow uses Dinic's algorithm; StoerWagnerMinCut finds the global minimum cut of an undirected network after
+// Stoer and Wagner, A simple min-cut algorithm, JACM 44(4), 1997.
+
+package Algorithms
+
+import (
+	"container/heap"
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"sort"
+)
+
+// flows smaller than this are treated as zero
+const flowEpsilon = 1e-9
+
+// Flow holds the positive flow along each edge in the direction it travels; for an undirected network only the net flow across an edge is kept.
+// SourceSide holds the vertices reachable from the source in the residual network; CutEdges are the edges from SourceSide to SinkSide, whose
+// capacities sum to Value.
+type FlowResult struct {
+	Value      float64
+	Flow       map[uint32]map[uint32]float64
+	SourceSide []uint32
+	SinkSide   []uint32
+	CutEdges   []Edge
+}
+
+type flowArc struct {
+	to       int
+	capacity float64
+	residual float64
+	reverse  int  // index of the paired arc in the adjacency list of to
+	original bool // false for the zero capacity reverse of a directed edge
+}
+
+type flowNetwork struct {
+	vertices []uint32
+	index    map[uint32]int
+	arcs     [][]flowArc
+	level    []int
+	next     []int
+}
+
+// Computes the maximum flow from source to sink.  Weights are capacities and must be non-negative; each edge of an undirected network
+// may carry flow in either direction.
+func MaxFlow(G *Core.Network, source uint32, sink uint32) (*FlowResult, error) {
+	return MaxFlowContext(context.Background(), G, source, sink, nil)
+}
+
+// As MaxFlow, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each phase of Dinic's algorithm
+func MaxFlowContext(ctx context.Context, G *Core.Network, source uint32, sink uint32, progress ProgressFunc) (*FlowResult, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if !G.HasVertex(source) || !G.HasVertex(sink) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Source %d and sink %d must both be in the network", source, sink))
+	}
+	if source == sink {
+		return nil, Core.NewNetworkArgumentError("Source and sink must differ")
+	}
+
+	F, err := newFlowNetwork(G)
+	if err != nil {
+		return nil, err
+	}
+	s := F.index[source]
+	t := F.index[sink]
+
+	retVal := new(FlowResult)
+	reporter := newProgressReporter("MaxFlowContext", progress)
+	for phase := 1; F.levels(s, t); phase++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for i := range F.next {
+			F.next[i] = 0
+		}
+		for {
+			pushed := F.augment(s, t, math.Inf(1))
+			if pushed <= flowEpsilon {
+				break
+			}
+			retVal.Value += pushed
+		}
+		reporter.emit(phase, -1)
+	}
+
+	retVal.Flow = make(map[uint32]map[uint32]float64)
+	for u, arcs := range F.arcs {
+		for _, arc := range arcs {
+			if !arc.original {
+				continue
+			}
+			// for undirected edges each arc of the pair reports the net flow in its own direction
+			flow := arc.capacity - arc.residual
+			if !G.Directed() {
+				flow = (flow - (F.arcs[arc.to][arc.reverse].capacity - F.arcs[arc.to][arc.reverse].residual)) / 2
+			}
+			if flow > flowEpsilon {
+				from := F.vertices[u]
+				if _, ok := retVal.Flow[from]; !ok {
+					retVal.Flow[from] = make(map[uint32]float64)
+				}
+				retVal.Flow[from][F.vertices[arc.to]] = flow
+			}
+		}
+	}
+
+	// the final level graph marks the vertices still reachable from the source
+	retVal.SourceSide = make([]uint32, 0)
+	retVal.SinkSide = make([]uint32, 0)
+	retVal.CutEdges = make([]Edge, 0)
+	for i, v := range F.vertices {
+		if F.level[i] >= 0 {
+			retVal.SourceSide = append(retVal.SourceSide, v)
+			for _, arc := range F.arcs[i] {
+				if arc.original && F.level[arc.to] < 0 {
+					retVal.CutEdges = append(retVal.CutEdges, Edge{From: v, To: F.vertices[arc.to], Weight: float32(arc.capacity)})
+				}
+			}
+		} else {
+			retVal.SinkSide = append(retVal.SinkSide, v)
+		}
+	}
+	sortEdges(retVal.CutEdges)
+	return retVal, nil
+}
+
+func newFlowNetwork(G *Core.Network) (*flowNetwork, error) {
+	F := new(flowNetwork)
+	F.vertices = G.Vertices(true)
+	F.index = make(map[uint32]int, len(F.vertices))
+	for i, v := range F.vertices {
+		F.index[v] = i
+	}
+	F.arcs = make([][]flowArc, len(F.vertices))
+	F.level = make([]int, len(F.vertices))
+	F.next = make([]int, len(F.vertices))
+
+	for _, from := range F.vertices {
+		u := F.index[from]
+		for _, edge := range traversalNeighbors(G, from, Outgoing) {
+			if edge.weight < 0 {
+				return nil, Core.NewNetworkArgumentError(Sprintf("Edge %d -> %d has negative capacity", from, edge.to))
+			}
+			v := F.index[edge.to]
+			if !G.Directed() && v < u {
+				// undirected neighbors are listed from both ends
+				continue
+			}
+			capacity := float64(edge.weight)
+			reverse := 0.0
+			if !G.Directed() {
+				reverse = capacity
+			}
+			F.arcs[u] = append(F.arcs[u], flowArc{to: v, capacity: capacity, residual: capacity, reverse: len(F.arcs[v]), original: true})
+			F.arcs[v] = append(F.arcs[v], flowArc{to: u, capacity: reverse, residual: reverse, reverse: len(F.arcs[u]) - 1, original: !G.Directed()})
+		}
+	}
+	return F, nil
+}
+
+// breadth-first search of the residual network; returns true if the sink is reachable
+func (F *flowNetwork) levels(s int, t int) bool {
+	for i := range F.level {
+		F.level[i] = -1
+	}
+	F.level[s] = 0
+	queue := []int{s}
+	for i := 0; i < len(queue); i++ {
+		u := queue[i]
+		for _, arc := range F.arcs[u] {
+			if arc.residual > flowEpsilon && F.level[arc.to] < 0 {
+				F.level[arc.to] = F.level[u] + 1
+				queue = append(queue, arc.to)
+			}
+		}
+	}
+	return F.level[t] >= 0
+}
+
+// pushes flow along one path of the level graph, skipping arcs already found to be blocked
+func (F *flowNetwork) augment(u int, t int, limit float64) float64 {
+	if u == t {
+		return limit
+	}
+	for ; F.next[u] < len(F.arcs[u]); F.next[u]++ {
+		arc := &F.arcs[u][F.next[u]]
+		if arc.residual <= flowEpsilon || F.level[arc.to] != F.level[u]+1 {
+			continue
+		}
+		pushed := F.augment(arc.to, t, math.Min(limit, arc.residual))
+		if pushed > flowEpsilon {
+			arc.residual -= pushed
+			F.arcs[arc.to][arc.reverse].residual += pushed
+			return pushed
+		}
+	}
+	return 0
+}
+
+// Finds a minimum weight set of edges whose removal disconnects an undirected network.  Returns the weight of the cut and the vertices on
+// each side; a disconnected network has a cut of weight zero.
+func StoerWagnerMinCut(G *Core.Network) (float64, []uint32, []uint32, error) {
+	return StoerWagnerMinCutContext(context.Background(), G, nil)
+}
+
+// As StoerWagnerMinCut, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each phase
+func StoerWagnerMinCutContext(ctx context.Context, G *Core.Network, progress ProgressFunc) (float64, []uint32, []uint32, error) {
+	if G == nil {
+		return 0, nil, nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if G.Directed() {
+		return 0, nil, nil, Core.NewNetworkArgumentError("Stoer-Wagner minimum cut requires an undirected network")
+	}
+	if G.Order() < 2 {
+		return 0, nil, nil, Core.NewNetworkArgumentError("A cut requires at least two vertices")
+	}
+
+	vertices := G.Vertices(true)
+	index := make(map[uint32]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+	// weights between merged vertices, and the original vertices each one holds
+	weights := make([]map[int]float64, len(vertices))
+	members := make([][]uint32, len(vertices))
+	for i, v := range vertices {
+		weights[i] = make(map[int]float64)
+		members[i] = []uint32{v}
+		for n, wt := range G.GetNeighbors(v) {
+			if wt < 0 {
+				return 0, nil, nil, Core.NewNetworkArgumentError(Sprintf("Edge %d - %d has negative weight", v, n))
+			}
+			weights[i][index[n]] = float64(wt)
+		}
+	}
+	alive := make([]bool, len(vertices))
+	for i := range alive {
+		alive[i] = true
+	}
+
+	best := math.Inf(1)
+	var bestSide []uint32
+	reporter := newProgressReporter("StoerWagnerMinCutContext", progress)
+	for phase := len(vertices); phase > 1; phase-- {
+		if err := ctx.Err(); err != nil {
+			return 0, nil, nil, err
+		}
+		if phase < len(vertices) {
+			reporter.emit(len(vertices)-phase, -1)
+		}
+		// maximum adjacency ordering: repeatedly add the vertex most tightly connected to those already added
+		start := 0
+		for !alive[start] {
+			start++
+		}
+		added := make(map[int]bool, phase)
+		key := make(map[int]float64)
+		queue := &adjacencyHeap{}
+		heap.Push(queue, adjacencyEntry{vertex: start})
+		s, t := -1, -1
+		for len(added) < phase {
+			if queue.Len() == 0 {
+				// the remaining vertices are disconnected from those added
+				for v := range alive {
+					if alive[v] && !added[v] {
+						heap.Push(queue, adjacencyEntry{vertex: v, key: key[v]})
+						break
+					}
+				}
+			}
+			entry := heap.Pop(queue).(adjacencyEntry)
+			if added[entry.vertex] || entry.key != key[entry.vertex] {
+				continue
+			}
+			added[entry.vertex] = true
+			s, t = t, entry.vertex
+			for n, wt := range weights[entry.vertex] {
+				if !added[n] {
+					key[n] += wt
+					heap.Push(queue, adjacencyEntry{vertex: n, key: key[n]})
+				}
+			}
+		}
+
+		if key[t] < best {
+			best = key[t]
+			bestSide = append([]uint32{}, members[t]...)
+		}
+
+		// merge t into s
+		for n, wt := range weights[t] {
+			delete(weights[n], t)
+			if n != s {
+				weights[s][n] += wt
+				weights[n][s] += wt
+			}
+		}
+		weights[t] = nil
+		members[s] = append(members[s], members[t]...)
+		alive[t] = false
+	}
+
+	inSide := make(map[uint32]bool, len(bestSide))
+	for _, v := range bestSide {
+		inSide[v] = true
+	}
+	other := make([]uint32, 0, len(vertices)-len(bestSide))
+	for _, v := range vertices {
+		if !inSide[v] {
+			other = append(other, v)
+		}
+	}
+	sort.Slice(bestSide, func(i, j int) bool { return bestSide[i] < bestSide[j] })
+	reporter.emit(len(vertices)-1, -1)
+	return best, bestSide, other, nil
+}
+
+type adjacencyEntry struct {
+	vertex int
+	key    float64
+}
+
+// max-heap on key, ties to the lower vertex index
+type adjacencyHeap []adjacencyEntry
+
+func (h adjacencyHeap) Len() int { return len(h) }
+func (h adjacencyHeap) Less(i, j int) bool {
+	if h[i].key != h[j].key {
+		return h[i].key > h[j].key
+	}
+	return h[i].vertex < h[j].vertex
+}
+func (h adjacencyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *adjacencyHeap) Push(x interface{}) { *h = append(*h, x.(adjacencyEntry)) }
+func (h *adjacencyHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
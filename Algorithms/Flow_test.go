@@ -0,0 +1,157 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// the cheapest s-t cut (or, if s == t, the cheapest cut of any kind) by enumerating every subset of the vertices 0..n-1
+func bruteCut(G *Core.Network, n int, s uint32, t uint32) float64 {
+	best := math.Inf(1)
+	for mask := 1; mask < (1<<uint(n))-1; mask++ {
+		if s != t && (mask&(1<<s) == 0 || mask&(1<<t) != 0) {
+			continue
+		}
+		cut := 0.0
+		for _, u := range G.Vertices(false) {
+			for v, wt := range G.GetNeighbors(u) {
+				// undirected neighbors are listed from both ends, so each crossing edge is seen once leaving the subset
+				if mask&(1<<u) != 0 && mask&(1<<v) == 0 {
+					cut += float64(wt)
+				}
+			}
+		}
+		if cut < best {
+			best = cut
+		}
+	}
+	return best
+}
+
+func TestMaxFlow(t *testing.T) {
+	// Cormen et al., figure 26.1
+	G := Core.NewNetwork(true)
+	_ = G.AddEdge(0, 1, 16)
+	_ = G.AddEdge(0, 2, 13)
+	_ = G.AddEdge(2, 1, 4)
+	_ = G.AddEdge(1, 3, 12)
+	_ = G.AddEdge(3, 2, 9)
+	_ = G.AddEdge(2, 4, 14)
+	_ = G.AddEdge(4, 3, 7)
+	_ = G.AddEdge(3, 5, 20)
+	_ = G.AddEdge(4, 5, 4)
+
+	result, err := MaxFlow(G, 0, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Value-23) > 1e-9 {
+		t.Errorf("Expected maximum flow 23, found %f", result.Value)
+	}
+	cut := 0.0
+	for _, edge := range result.CutEdges {
+		cut += float64(edge.Weight)
+	}
+	if math.Abs(cut-23) > 1e-9 {
+		t.Errorf("Expected cut capacity 23, found %f from %v", cut, result.CutEdges)
+	}
+	// conservation at every interior vertex and capacity on every edge
+	for _, v := range G.Vertices(false) {
+		balance := 0.0
+		for to, f := range result.Flow[v] {
+			if f > float64(G.EdgeWeight(v, to))+1e-9 {
+				t.Errorf("Flow %f on %d -> %d exceeds capacity", f, v, to)
+			}
+			balance -= f
+		}
+		for from := range G.GetSources(v) {
+			balance += result.Flow[from][v]
+		}
+		if v != 0 && v != 5 && math.Abs(balance) > 1e-9 {
+			t.Errorf("Flow is not conserved at %d", v)
+		}
+	}
+
+	r := rand.New(rand.NewSource(35))
+	for trial := 0; trial < 30; trial++ {
+		directed := trial%2 == 0
+		H := randomWeighted(7, 0.4, directed, r)
+		result, err := MaxFlow(H, 0, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := bruteCut(H, 7, 0, 6)
+		if math.Abs(result.Value-expected) > 1e-6 {
+			t.Errorf("Expected maximum flow %f (directed %v), found %f", expected, directed, result.Value)
+		}
+		if len(result.SourceSide)+len(result.SinkSide) != 7 || result.SourceSide[0] != 0 {
+			t.Errorf("Invalid cut partition %v | %v", result.SourceSide, result.SinkSide)
+		}
+	}
+
+	if _, err = MaxFlow(G, 0, 0); err == nil {
+		t.Errorf("Expected an error when source and sink coincide")
+	}
+}
+
+func TestStoerWagner(t *testing.T) {
+	// the example from Stoer and Wagner's paper, renumbered from 0
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(0, 1, 2)
+	_ = G.AddEdge(0, 4, 3)
+	_ = G.AddEdge(1, 2, 3)
+	_ = G.AddEdge(1, 4, 2)
+	_ = G.AddEdge(1, 5, 2)
+	_ = G.AddEdge(2, 3, 4)
+	_ = G.AddEdge(2, 6, 2)
+	_ = G.AddEdge(3, 6, 2)
+	_ = G.AddEdge(3, 7, 2)
+	_ = G.AddEdge(4, 5, 3)
+	_ = G.AddEdge(5, 6, 1)
+	_ = G.AddEdge(6, 7, 3)
+
+	weight, side, other, err := StoerWagnerMinCut(G)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if weight != 4 || len(side)+len(other) != 8 {
+		t.Errorf("Expected a cut of weight 4, found %f between %v and %v", weight, side, other)
+	}
+
+	r := rand.New(rand.NewSource(36))
+	for trial := 0; trial < 30; trial++ {
+		H := randomWeighted(7, 0.35, false, r)
+		weight, side, other, _ := StoerWagnerMinCut(H)
+		expected := bruteCut(H, 7, 0, 0)
+		if math.Abs(weight-expected) > 1e-6 || len(side) == 0 || len(other) == 0 {
+			t.Errorf("Expected minimum cut %f, found %f between %v and %v", expected, weight, side, other)
+		}
+	}
+
+	if _, _, _, err = StoerWagnerMinCut(Core.NewNetwork(true)); err == nil {
+		t.Errorf("Expected an error for a directed network")
+	}
+}
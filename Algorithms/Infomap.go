@@ -0,0 +1,457 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Two-level Infomap community detection, minimizing the map equation: Rosvall, Martin and Bergstrom, Carl T., Maps of Random Walks on Complex
+// Networks Reveal Community Structure, Proceedings of the National Academy of Sciences 105(4):1118-1123, 2008.
+// Flow on directed networks is PageRank with recorded teleportation to vertices chosen uniformly; on undirected networks it is proportional to
+// strength.  The map equation is minimized by Louvain-style local moving and aggregation.
+
+package Algorithms
+
+import (
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+type InfomapOptions struct {
+	Teleportation float64 // probability that the random walker on a directed network jumps to a random vertex
+	Seed          int64
+	Trials        int     // independent attempts, from Seed, Seed + 1, ...; the shortest codelength is kept
+	Tolerance     float64 // a pass shortening the codelength by less than this ends local moving
+}
+
+func NewInfomapOptions() *InfomapOptions {
+	options := new(InfomapOptions)
+	options.Teleportation = 0.15
+	options.Seed = 1
+	options.Trials = 1
+	options.Tolerance = 1e-10
+	return options
+}
+
+// Modules assigns every vertex to a module, numbered from 0 in order of the lowest member vertex id; Communities lists the members of each module
+// as ConcurrentSLPA does.  Codelengths are in bits per step of the random walk; OneLevelCodelength is that of a single module, the entropy of the flow.
+type InfomapResult struct {
+	Modules            map[uint32]int
+	Communities        map[int][]uint32
+	Codelength         float64
+	OneLevelCodelength float64
+	Flow               map[uint32]float64 // stationary visit rate of each vertex
+}
+
+func Infomap(G *Core.Network, options *InfomapOptions) (*InfomapResult, error) {
+	return InfomapContext(context.Background(), G, options, nil)
+}
+
+// As Infomap, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each level of each trial
+func InfomapContext(ctx context.Context, G *Core.Network, options *InfomapOptions, progress ProgressFunc) (*InfomapResult, error) {
+	if options == nil {
+		return nil, Core.NewNetworkArgumentNullError("Options must be non-null")
+	}
+	if options.Teleportation < 0 || options.Teleportation >= 1 {
+		return nil, Core.NewNetworkArgumentError("Teleportation must lie in [0, 1)")
+	}
+	if options.Trials < 1 {
+		return nil, Core.NewNetworkArgumentError("Infomap requires at least one trial")
+	}
+	g, vertices, err := newFlowGraph(G, options.Teleportation)
+	if err != nil {
+		return nil, err
+	}
+
+	retVal := new(InfomapResult)
+	retVal.Flow = make(map[uint32]float64, len(vertices))
+	entropy := 0.0
+	for i, v := range vertices {
+		retVal.Flow[v] = g.flow[i]
+		entropy -= plogp(g.flow[i])
+	}
+	retVal.OneLevelCodelength = entropy
+
+	reporter := newProgressReporter("InfomapContext", progress)
+	best := make([]int, len(vertices))
+	retVal.Codelength = entropy
+	for trial := 0; trial < options.Trials; trial++ {
+		modules, codelength, err := g.infomapTrial(ctx, rand.New(rand.NewSource(options.Seed+int64(trial))), options.Tolerance, reporter)
+		if err != nil {
+			return nil, err
+		}
+		if codelength < retVal.Codelength-moveEpsilon {
+			retVal.Codelength = codelength
+			best = modules
+		}
+	}
+
+	renumberCommunities(best)
+	retVal.Modules = make(map[uint32]int, len(vertices))
+	retVal.Communities = make(map[int][]uint32)
+	for i, v := range vertices {
+		retVal.Modules[v] = best[i]
+		retVal.Communities[best[i]] = append(retVal.Communities[best[i]], v)
+	}
+	return retVal, nil
+}
+
+// Flow network of nodes 0..n-1: the visit rate of each node, the rate at which the walker teleports away from it, the number of vertices it holds,
+// and the flow along arcs; flow along arcs within a node, left by aggregation, is held in self.
+type flowGraph struct {
+	out      [][]weightedArc
+	in       [][]weightedArc
+	flow     []float64
+	teleport []float64
+	size     []float64
+	self     []float64
+	outFlow  []float64 // flow leaving each node along arcs, including arcs within the node
+	vertices float64   // number of vertices of the original network
+}
+
+func newFlowGraph(G *Core.Network, teleportation float64) (*flowGraph, []uint32, error) {
+	if G == nil {
+		return nil, nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	vertices := G.Vertices(true)
+	n := len(vertices)
+	index := make(map[uint32]int, n)
+	for i, v := range vertices {
+		index[v] = i
+	}
+
+	g := newEmptyFlowGraph(n)
+	g.vertices = float64(n)
+	weights := make([][]weightedArc, n)
+	strength := make([]float64, n)
+	total := 0.0
+	for i, u := range vertices {
+		g.size[i] = 1
+		for _, nb := range traversalNeighbors(G, u, Outgoing) {
+			if nb.weight < 0 {
+				return nil, nil, Core.NewNetworkArgumentError(Sprintf("Edge %d - %d has negative weight", u, nb.to))
+			}
+			weights[i] = append(weights[i], weightedArc{to: index[nb.to], weight: float64(nb.weight)})
+			strength[i] += float64(nb.weight)
+			total += float64(nb.weight)
+		}
+	}
+	if n == 0 {
+		return g, vertices, nil
+	}
+
+	if !G.Directed() || total == 0 {
+		// flow is proportional to strength and needs no teleportation; both arcs of an undirected edge are held in weights
+		for i := range vertices {
+			if total > 0 {
+				g.flow[i] = strength[i] / total
+			} else {
+				g.flow[i] = 1 / float64(n)
+			}
+			for _, arc := range weights[i] {
+				g.addArc(i, arc.to, arc.weight/total)
+			}
+		}
+		return g, vertices, nil
+	}
+
+	// PageRank by power iteration; dangling vertices always teleport
+	flow := make([]float64, n)
+	for i := range flow {
+		flow[i] = 1 / float64(n)
+	}
+	for iteration := 0; iteration < 1000; iteration++ {
+		jump := 0.0
+		for i := range flow {
+			if strength[i] == 0 {
+				jump += flow[i]
+			} else {
+				jump += teleportation * flow[i]
+			}
+		}
+		next := make([]float64, n)
+		for i := range next {
+			next[i] = jump / float64(n)
+		}
+		for i := range flow {
+			for _, arc := range weights[i] {
+				next[arc.to] += (1 - teleportation) * flow[i] * arc.weight / strength[i]
+			}
+		}
+		change := 0.0
+		for i := range flow {
+			change += math.Abs(next[i] - flow[i])
+		}
+		flow = next
+		if change < 1e-15 {
+			break
+		}
+	}
+
+	for i := range vertices {
+		g.flow[i] = flow[i]
+		if strength[i] == 0 {
+			g.teleport[i] = flow[i]
+			continue
+		}
+		g.teleport[i] = teleportation * flow[i]
+		for _, arc := range weights[i] {
+			g.addArc(i, arc.to, (1-teleportation)*flow[i]*arc.weight/strength[i])
+		}
+	}
+	return g, vertices, nil
+}
+
+func newEmptyFlowGraph(n int) *flowGraph {
+	g := new(flowGraph)
+	g.out = make([][]weightedArc, n)
+	g.in = make([][]weightedArc, n)
+	g.flow = make([]float64, n)
+	g.teleport = make([]float64, n)
+	g.size = make([]float64, n)
+	g.self = make([]float64, n)
+	g.outFlow = make([]float64, n)
+	return g
+}
+
+func (g *flowGraph) addArc(from int, to int, flow float64) {
+	g.out[from] = append(g.out[from], weightedArc{to: to, weight: flow})
+	g.in[to] = append(g.in[to], weightedArc{to: from, weight: flow})
+	g.outFlow[from] += flow
+}
+
+func (g *flowGraph) order() int {
+	return len(g.flow)
+}
+
+// sums over the nodes of a module
+type flowModule struct {
+	flow     float64
+	teleport float64
+	size     float64
+	outFlow  float64
+	internal float64 // flow along arcs between members, or within them
+}
+
+// rate at which the walker leaves the module, along arcs or by teleporting to a vertex outside it
+func (m flowModule) exit(vertices float64) float64 {
+	return m.teleport*(vertices-m.size)/vertices + m.outFlow - m.internal
+}
+
+func plogp(p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	return p * math.Log2(p)
+}
+
+// Map equation state: the modules and the sum of their exit rates
+type mapEquation struct {
+	g       *flowGraph
+	modules []flowModule
+	exit    float64
+}
+
+func newMapEquation(g *flowGraph, module []int) *mapEquation {
+	m := &mapEquation{g: g, modules: make([]flowModule, g.order())}
+	for i, c := range module {
+		m.modules[c].flow += g.flow[i]
+		m.modules[c].teleport += g.teleport[i]
+		m.modules[c].size += g.size[i]
+		m.modules[c].outFlow += g.outFlow[i]
+		m.modules[c].internal += g.self[i]
+		for _, arc := range g.out[i] {
+			if module[arc.to] == c {
+				m.modules[c].internal += arc.weight
+			}
+		}
+	}
+	for _, mod := range m.modules {
+		m.exit += mod.exit(g.vertices)
+	}
+	return m
+}
+
+// the codelength, less the entropy of the node visit rates, which does not change as nodes move
+func (m *mapEquation) moduleCodelength() float64 {
+	retVal := plogp(m.exit)
+	for _, mod := range m.modules {
+		q := mod.exit(m.g.vertices)
+		retVal += -2*plogp(q) + plogp(q+mod.flow)
+	}
+	return retVal
+}
+
+func (m *mapEquation) codelength() float64 {
+	retVal := m.moduleCodelength()
+	for _, p := range m.g.flow {
+		retVal -= plogp(p)
+	}
+	return retVal
+}
+
+// the modules a and b with node i moved from a to b, given the flow from i into each and from each into i
+func (m *mapEquation) moved(i int, a int, b int, toA float64, fromA float64, toB float64, fromB float64) (flowModule, flowModule) {
+	g := m.g
+	oldA, oldB := m.modules[a], m.modules[b]
+	newA := flowModule{flow: oldA.flow - g.flow[i], teleport: oldA.teleport - g.teleport[i], size: oldA.size - g.size[i],
+		outFlow: oldA.outFlow - g.outFlow[i], internal: oldA.internal - toA - fromA - g.self[i]}
+	newB := flowModule{flow: oldB.flow + g.flow[i], teleport: oldB.teleport + g.teleport[i], size: oldB.size + g.size[i],
+		outFlow: oldB.outFlow + g.outFlow[i], internal: oldB.internal + toB + fromB + g.self[i]}
+	return newA, newB
+}
+
+// the change in codelength from the move
+func (m *mapEquation) delta(a int, b int, newA flowModule, newB flowModule) float64 {
+	v := m.g.vertices
+	oldA, oldB := m.modules[a], m.modules[b]
+	qa, qb, qa2, qb2 := oldA.exit(v), oldB.exit(v), newA.exit(v), newB.exit(v)
+	exit := m.exit - qa - qb + qa2 + qb2
+	return plogp(exit) - plogp(m.exit) - 2*(plogp(qa2)+plogp(qb2)-plogp(qa)-plogp(qb)) +
+		plogp(qa2+newA.flow) + plogp(qb2+newB.flow) - plogp(qa+oldA.flow) - plogp(qb+oldB.flow)
+}
+
+func (m *mapEquation) apply(a int, b int, newA flowModule, newB flowModule) {
+	v := m.g.vertices
+	m.exit += newA.exit(v) + newB.exit(v) - m.modules[a].exit(v) - m.modules[b].exit(v)
+	m.modules[a] = newA
+	m.modules[b] = newB
+}
+
+// local moving in random order; updates module and returns true if any node moved
+func (g *flowGraph) moveNodes(ctx context.Context, module []int, r *rand.Rand, tolerance float64) (bool, error) {
+	m := newMapEquation(g, module)
+	moved := false
+	codelength := m.moduleCodelength()
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		moves := 0
+		for _, i := range r.Perm(g.order()) {
+			toModule := make(map[int]float64)
+			fromModule := make(map[int]float64)
+			for _, arc := range g.out[i] {
+				toModule[module[arc.to]] += arc.weight
+			}
+			for _, arc := range g.in[i] {
+				fromModule[module[arc.to]] += arc.weight
+			}
+			candidates := make([]int, 0, len(toModule)+len(fromModule))
+			for c := range toModule {
+				candidates = append(candidates, c)
+			}
+			for c := range fromModule {
+				if _, ok := toModule[c]; !ok {
+					candidates = append(candidates, c)
+				}
+			}
+			sort.Ints(candidates)
+
+			a := module[i]
+			best, bestDelta := a, -moveEpsilon
+			var bestA, bestB flowModule
+			for _, b := range candidates {
+				if b == a {
+					continue
+				}
+				newA, newB := m.moved(i, a, b, toModule[a], fromModule[a], toModule[b], fromModule[b])
+				if d := m.delta(a, b, newA, newB); d < bestDelta {
+					best, bestDelta, bestA, bestB = b, d, newA, newB
+				}
+			}
+			if best != a {
+				m.apply(a, best, bestA, bestB)
+				module[i] = best
+				moves++
+			}
+		}
+		if moves == 0 {
+			break
+		}
+		moved = true
+		next := m.moduleCodelength()
+		if codelength-next < tolerance {
+			break
+		}
+		codelength = next
+	}
+	return moved, nil
+}
+
+// collapses each module to a node; module ids must run from 0 to count-1
+func (g *flowGraph) aggregate(module []int, count int) *flowGraph {
+	retVal := newEmptyFlowGraph(count)
+	retVal.vertices = g.vertices
+	flows := make([]map[int]float64, count)
+	for c := range flows {
+		flows[c] = make(map[int]float64)
+	}
+	for i, c := range module {
+		retVal.flow[c] += g.flow[i]
+		retVal.teleport[c] += g.teleport[i]
+		retVal.size[c] += g.size[i]
+		retVal.self[c] += g.self[i]
+		retVal.outFlow[c] += g.self[i]
+		for _, arc := range g.out[i] {
+			if d := module[arc.to]; d == c {
+				retVal.self[c] += arc.weight
+				retVal.outFlow[c] += arc.weight
+			} else {
+				flows[c][d] += arc.weight
+			}
+		}
+	}
+	for c, targets := range flows {
+		ids := make([]int, 0, len(targets))
+		for d := range targets {
+			ids = append(ids, d)
+		}
+		sort.Ints(ids)
+		for _, d := range ids {
+			retVal.addArc(c, d, targets[d])
+		}
+	}
+	return retVal
+}
+
+// one attempt from singleton modules, returning the module of each original node and the codelength
+func (g *flowGraph) infomapTrial(ctx context.Context, r *rand.Rand, tolerance float64, reporter *progressReporter) ([]int, float64, error) {
+	nodeOf := identityAssignment(g.order())
+	current := g
+	for level := 0; ; level++ {
+		module := identityAssignment(current.order())
+		moved, err := current.moveNodes(ctx, module, r, tolerance)
+		if err != nil {
+			return nil, 0, err
+		}
+		count := renumberCommunities(module)
+		if !moved || count == current.order() {
+			break
+		}
+		for v := range nodeOf {
+			nodeOf[v] = module[nodeOf[v]]
+		}
+		reporter.emit(level+1, -1)
+		current = current.aggregate(module, count)
+	}
+	return nodeOf, newMapEquation(g, nodeOf).codelength(), nil
+}
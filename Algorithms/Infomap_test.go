@@ -0,0 +1,128 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestInfomapTwoCliques(t *testing.T) {
+	result, err := Infomap(makeTwoCliques(), NewInfomapOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int][]uint32{0: {0, 1, 2, 3, 4, 5}, 1: {10, 11, 12, 13, 14, 15}}
+	if !reflect.DeepEqual(result.Communities, expected) {
+		t.Errorf("Expected the two cliques, found %v", result.Communities)
+	}
+	if result.Modules[3] != 0 || result.Modules[13] != 1 {
+		t.Errorf("Module assignment disagrees with the communities")
+	}
+	if result.Codelength >= result.OneLevelCodelength {
+		t.Errorf("Expected the two modules to shorten the codelength of %f, found %f", result.OneLevelCodelength, result.Codelength)
+	}
+
+	// 62 arc ends: vertices 5 and 10 have strength 6, the rest 5; each clique exits along one of its 31 arcs
+	p5, p6 := 5.0/62.0, 6.0/62.0
+	exit := 1.0 / 62.0
+	entropy := -(10*p5*math.Log2(p5) + 2*p6*math.Log2(p6))
+	module := 0.5 + exit
+	expectedLength := 2*exit*math.Log2(2*exit) - 4*exit*math.Log2(exit) + 2*module*math.Log2(module) - (10*p5*math.Log2(p5) + 2*p6*math.Log2(p6))
+	if math.Abs(result.OneLevelCodelength-entropy) > 1e-9 || math.Abs(result.Codelength-expectedLength) > 1e-9 {
+		t.Errorf("Expected codelengths %f and %f, found %f and %f", entropy, expectedLength, result.OneLevelCodelength, result.Codelength)
+	}
+}
+
+func TestInfomapDirected(t *testing.T) {
+	// two directed 5-cycles with chords, joined in both directions by single arcs
+	G := Core.NewNetwork(true)
+	for _, base := range []uint32{0, 10} {
+		for i := uint32(0); i < 5; i++ {
+			_ = G.AddEdge(base+i, base+(i+1)%5, 1.0)
+			_ = G.AddEdge(base+i, base+(i+2)%5, 1.0)
+		}
+	}
+	_ = G.AddEdge(4, 10, 1.0)
+	_ = G.AddEdge(14, 0, 1.0)
+	result, err := Infomap(G, NewInfomapOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int][]uint32{0: {0, 1, 2, 3, 4}, 1: {10, 11, 12, 13, 14}}
+	if !reflect.DeepEqual(result.Communities, expected) {
+		t.Errorf("Expected the two cycles, found %v", result.Communities)
+	}
+	total := 0.0
+	for _, p := range result.Flow {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected the flow to sum to 1, found %f", total)
+	}
+	// the network is symmetric under exchanging the cycles
+	if math.Abs(result.Flow[2]-result.Flow[12]) > 1e-9 {
+		t.Errorf("Expected equal flow at corresponding vertices, found %f and %f", result.Flow[2], result.Flow[12])
+	}
+}
+
+func TestInfomapPlanted(t *testing.T) {
+	probs := [][]float64{{0.4, 0.01, 0.01}, {0.01, 0.4, 0.01}, {0.01, 0.01, 0.4}}
+	G, planted, _ := Generators.StochasticBlockModel([]int{30, 30, 30}, probs, true, rand.New(rand.NewSource(8)))
+	options := NewInfomapOptions()
+	result, err := Infomap(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agreement := pairAgreement(result.Communities, planted); agreement < 0.95 {
+		t.Errorf("Expected the planted blocks to be recovered, found pair agreement %f", agreement)
+	}
+
+	options.Trials = 4
+	better, _ := Infomap(G, options)
+	if better.Codelength > result.Codelength+1e-12 {
+		t.Errorf("More trials lengthened the codelength from %f to %f", result.Codelength, better.Codelength)
+	}
+
+	// without structure, one module describes the walk best
+	R, _ := Generators.ErdosRenyiGnp(60, 0.3, false, rand.New(rand.NewSource(2)))
+	random, _ := Infomap(R, NewInfomapOptions())
+	if len(random.Communities) != 1 || random.Codelength != random.OneLevelCodelength {
+		t.Errorf("Expected a single module for a dense random network, found %d", len(random.Communities))
+	}
+}
+
+func TestInfomapArguments(t *testing.T) {
+	options := NewInfomapOptions()
+	options.Teleportation = 1
+	if _, err := Infomap(makeTwoCliques(), options); err == nil {
+		t.Error("Expected an error for teleportation of 1")
+	}
+	result, err := Infomap(Core.NewNetwork(true), NewInfomapOptions())
+	if err != nil || len(result.Communities) != 0 {
+		t.Errorf("Expected no modules in an empty network")
+	}
+}
@@ -0,0 +1,432 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Label propagation producing hard partitions: each vertex takes the label carried by most of its neighbors until no vertex would change.
+// AsynchronousLPA is the algorithm of Raghavan, Usha Nandini, Albert, Réka, and Kumara, Soundar, Near Linear Time Algorithm to Detect Community
+// Structures in Large-scale Networks, Physical Review E 76, 036106, 2007.  After a synchronous first iteration its partitions run concurrently
+// under the dependency control of ConcurrentSLPA.  SemiSynchronousLPA is that of Cordasco, Gennaro and Gargano, Luisa, Community Detection via Semi-synchronous Label Propagation
+// Algorithms, IEEE International Workshop on Business Applications of Social Network Analysis, 2010.  It updates the color classes of a proper
+// coloring in turn; each class updates at once.  It always converges on undirected networks with positive weights.
+// A vertex keeps its label whenever that label ties for the most votes, otherwise ties are broken at random.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type LPAMode int
+
+const (
+	AsynchronousLPA    LPAMode = iota // vertices update one at a time in random order, hearing the labels as they stand
+	SynchronousLPA                    // every vertex updates at once from the labels of the previous iteration; may oscillate on bipartite structure
+	SemiSynchronousLPA                // the color classes of a greedy coloring update in turn
+)
+
+type LPAOptions struct {
+	Mode            LPAMode
+	MaxIterations   int
+	Seed            int64
+	ConcurrentCount int
+	// Weighted scales each neighbor's vote by the weight of the edge joining it to the vertex
+	Weighted bool
+	// Direction selects the neighbors heard on directed networks, as for SLPAOptions
+	Direction TraversalDirection
+	// Partitions, if not nil, assigns the vertices to goroutines in place of ConcurrentCount contiguous ranges, as for SLPAOptions
+	Partitions [][]uint32
+}
+
+func NewLPAOptions() *LPAOptions {
+	options := new(LPAOptions)
+	options.Mode = AsynchronousLPA
+	options.MaxIterations = 100
+	options.Seed = 1
+	options.ConcurrentCount = runtime.NumCPU()
+	options.Weighted = false
+	options.Direction = Outgoing
+	return options
+}
+
+// Communities found by label propagation, keyed as those returned by ConcurrentSLPA and numbered from 0 in order of their lowest member.  Each vertex
+// belongs to exactly one community; a vertex no other vertex labels forms a community of its own.
+func LabelPropagation(G *Core.Network, options *LPAOptions) (map[int][]uint32, error) {
+	return LabelPropagationContext(context.Background(), G, options, nil)
+}
+
+// As LabelPropagation, but stops with the context's error if ctx is cancelled, and reports progress if progress is not nil.  The asynchronous mode
+// reports per partition as it completes an iteration; the others report once per iteration for the whole network.
+func LabelPropagationContext(ctx context.Context, G *Core.Network, options *LPAOptions, progress ProgressFunc) (map[int][]uint32, error) {
+	if err := checkLPAOptions(G, options); err != nil {
+		return nil, err
+	}
+	if G.Order() == 0 {
+		return make(map[int][]uint32), nil
+	}
+
+	state := newLPAState(G, options)
+	partitions := vertexPartitions(G, options.Partitions, options.ConcurrentCount)
+	reporter := newProgressReporter("LabelPropagationContext", progress)
+	var err error
+	switch options.Mode {
+	case SynchronousLPA:
+		err = state.synchronous(ctx, partitions, nil, options, reporter)
+	case SemiSynchronousLPA:
+		colors, _ := GreedyColoring(G, LargestFirst)
+		err = state.synchronous(ctx, partitions, colors, options, reporter)
+	default:
+		err = state.asynchronous(ctx, partitions, options, reporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return state.communities(), nil
+}
+
+func checkLPAOptions(G *Core.Network, options *LPAOptions) error {
+	if G == nil {
+		return Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if options == nil {
+		return Core.NewNetworkArgumentNullError("Options must be non-null")
+	}
+	if options.MaxIterations < 1 {
+		return Core.NewNetworkArgumentError("Label propagation requires at least one iteration")
+	}
+	if options.ConcurrentCount < 1 {
+		return Core.NewNetworkArgumentError("ConcurrentCount must be at least 1")
+	}
+	if options.Mode < AsynchronousLPA || options.Mode > SemiSynchronousLPA {
+		return Core.NewNetworkArgumentError("Unknown label propagation mode")
+	}
+	if options.Partitions != nil {
+		return ValidatePartitions(G, options.Partitions)
+	}
+	return nil
+}
+
+// the vertices in ascending order, the neighbors each hears by index, and the current label of each; labels are indices into vertices and
+// are read and written atomically, as partitions read the labels of their neighbors in other partitions
+type lpaState struct {
+	G        *Core.Network
+	rules    *slpaRules
+	vertices []uint32
+	vertIdx  map[uint32]int
+	speakers [][]indexedSpeaker
+	labels   []int32
+}
+
+func newLPAState(G *Core.Network, options *LPAOptions) *lpaState {
+	state := &lpaState{G: G, rules: &slpaRules{direction: options.Direction, weighted: options.Weighted}}
+	state.vertices = G.Vertices(true)
+	order := len(state.vertices)
+	state.vertIdx = make(map[uint32]int, order)
+	for idx, vert := range state.vertices {
+		state.vertIdx[vert] = idx
+	}
+	state.speakers = make([][]indexedSpeaker, order)
+	state.labels = make([]int32, order)
+	for idx, vert := range state.vertices {
+		// a self-loop is not a vote for keeping the label
+		for _, edge := range state.rules.speakers(G, vert) {
+			if edge.to != vert {
+				state.speakers[idx] = append(state.speakers[idx], indexedSpeaker{index: state.vertIdx[edge.to], vote: state.rules.vote(edge.weight)})
+			}
+		}
+		state.labels[idx] = int32(idx)
+	}
+	return state
+}
+
+// the labels carrying the most votes among the neighbors of idx, ascending, and whether the current label is one of them
+func (state *lpaState) leaders(idx int) ([]int32, bool) {
+	if len(state.speakers[idx]) == 0 {
+		return nil, true
+	}
+	votes := make(map[int32]float64)
+	for _, speaker := range state.speakers[idx] {
+		votes[atomic.LoadInt32(&state.labels[speaker.index])] += speaker.vote
+	}
+	var best float64
+	tied := make([]int32, 0, 1)
+	for label, vote := range votes {
+		if len(tied) == 0 || vote > best {
+			best = vote
+			tied = append(tied[:0], label)
+		} else if vote == best {
+			tied = append(tied, label)
+		}
+	}
+	sort.Slice(tied, func(i, j int) bool { return tied[i] < tied[j] })
+	current := atomic.LoadInt32(&state.labels[idx])
+	for _, label := range tied {
+		if label == current {
+			return tied, true
+		}
+	}
+	return tied, false
+}
+
+// the label idx adopts: its own if that ties for the most votes, otherwise one of the leaders at random
+func (state *lpaState) choose(idx int, r SLPARandom) int32 {
+	tied, keep := state.leaders(idx)
+	if keep {
+		return atomic.LoadInt32(&state.labels[idx])
+	}
+	return tied[r.Intn(len(tied))]
+}
+
+// true if no vertex would change its label
+func (state *lpaState) stable() bool {
+	for idx := range state.vertices {
+		if _, keep := state.leaders(idx); !keep {
+			return false
+		}
+	}
+	return true
+}
+
+// updates the vertices at indices in random order, counting the changes; returns false, leaving the pass incomplete, if done is closed
+func (state *lpaState) relabel(indices []int, r *rand.Rand, changed *int64, done <-chan struct{}) bool {
+	for _, i := range r.Perm(len(indices)) {
+		select {
+		case <-done:
+			return false
+		default:
+		}
+		idx := indices[i]
+		label := state.choose(idx, r)
+		if label != atomic.LoadInt32(&state.labels[idx]) {
+			atomic.StoreInt32(&state.labels[idx], label)
+			atomic.AddInt64(changed, 1)
+		}
+	}
+	return true
+}
+
+// runs asynchronous propagation under the control structure of ConcurrentSLPA.  The controller stops the goroutines once every partition has
+// completed an iteration without a change; since a partition may have read labels another was still changing, the labels are then checked and
+// propagation resumes, within MaxIterations, if they are not stable.
+func (state *lpaState) asynchronous(ctx context.Context, partitionSlices [][]uint32, options *LPAOptions, reporter *progressReporter) error {
+	concurrentCount := len(partitionSlices)
+	dependsOnList, dependencyToList, internals, externals := partitionDependencies(state.G, partitionSlices, state.rules.speakers)
+	toIndices := func(vertices []uint32) []int {
+		retVal := make([]int, len(vertices))
+		for i, vert := range vertices {
+			retVal[i] = state.vertIdx[vert]
+		}
+		return retVal
+	}
+	internalIndices := make([][]int, concurrentCount)
+	externalIndices := make([][]int, concurrentCount)
+	for i := 0; i < concurrentCount; i++ {
+		internalIndices[i] = toIndices(internals[i])
+		externalIndices[i] = toIndices(externals[i])
+	}
+
+	// the first iteration is synchronous, every vertex taking a label from the initial labels of its neighbors; otherwise a partition running ahead
+	// settles its labels first and they flood the partitions that start later, merging communities that follow partition boundaries
+	first := *options
+	first.MaxIterations = 1
+	if err := state.synchronous(ctx, partitionSlices, nil, &first, nil); err != nil {
+		return err
+	}
+
+	for run, remaining := 0, options.MaxIterations-1; remaining > 0; run++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		iterations := remaining
+		changed := make([]int64, iterations)
+		completed := make([]int, concurrentCount)
+
+		// the first iteration not yet known to have changed something; partition p has finished iteration i once it asks to begin iteration i + 2
+		// or reports it is done
+		checked := 0
+		stop := func(currentIteration []int) bool {
+			for ; checked < iterations; checked++ {
+				for _, iteration := range currentIteration {
+					if iteration != -1 && iteration < checked+2 {
+						return false
+					}
+				}
+				if atomic.LoadInt64(&changed[checked]) == 0 {
+					return true
+				}
+			}
+			return false
+		}
+
+		canIGoChannel := make(chan IterationMessage, concurrentCount)
+		goChannels := make([]chan bool, concurrentCount)
+		done := make(chan struct{})
+		var routines sync.WaitGroup
+		for i := 0; i < concurrentCount; i++ {
+			goChannels[i] = make(chan bool, 1)
+			routines.Add(1)
+			go func(i int) {
+				defer routines.Done()
+				r := rand.New(rand.NewSource(options.Seed + int64(run*concurrentCount+i)))
+				completed[i] = state.partitionLPA(i, externalIndices[i], internalIndices[i], iterations, r, changed, canIGoChannel, goChannels[i], done, reporter)
+			}(i)
+		}
+		if err := controlPartitions(ctx, dependsOnList, dependencyToList, canIGoChannel, goChannels, done, &routines, stop); err != nil {
+			return err
+		}
+		routines.Wait()
+
+		if state.stable() {
+			return nil
+		}
+		most := 1
+		for _, count := range completed {
+			if count > most {
+				most = count
+			}
+		}
+		remaining -= most
+	}
+	return nil
+}
+
+// one goroutine of asynchronous propagation, following partitionSLPA: relabel the vertices with neighbors in other partitions, ask the controller
+// for permission to go on, then relabel the vertices whose neighbors all lie within the partition.  Returns the number of iterations completed.
+func (state *lpaState) partitionLPA(routineID int, externals []int, internals []int, iterations int, r *rand.Rand, changed []int64, askChannel chan<- IterationMessage, waitChannel <-chan bool, done <-chan struct{}, reporter *progressReporter) int {
+	for i := 0; i < iterations; i++ {
+		if !state.relabel(externals, r, &changed[i], done) {
+			return i
+		}
+
+		if i < iterations-1 {
+			permissionSlip := IterationMessage{RoutineId: routineID, IterationNumber: i + 1}
+			select {
+			case askChannel <- permissionSlip:
+			case <-done:
+				return i
+			}
+			select {
+			case proceed := <-waitChannel:
+				if !proceed {
+					// the controller has seen an iteration without change; report finished
+					select {
+					case askChannel <- IterationMessage{RoutineId: routineID, IterationNumber: -1}:
+					case <-done:
+					}
+					return i
+				}
+			case <-done:
+				return i
+			}
+		}
+
+		if !state.relabel(internals, r, &changed[i], done) {
+			return i
+		}
+		reporter.emit(i+1, routineID)
+	}
+
+	// wait to send termination as we don't want to drop out of the control loop
+	select {
+	case askChannel <- IterationMessage{RoutineId: routineID, IterationNumber: -1}:
+	case <-done:
+	}
+	return iterations
+}
+
+// runs synchronous propagation, or semi-synchronous propagation if colors is not nil, with the vertices of each partition updated concurrently.
+// Every vertex in a round chooses from the labels as they stood at the start of the round, drawing from a random stream determined by the seed,
+// the iteration, and the vertex, so the result does not depend on the partitions.  Stops after an iteration without a change.
+func (state *lpaState) synchronous(ctx context.Context, partitionSlices [][]uint32, colors map[uint32]int, options *LPAOptions, reporter *progressReporter) error {
+	// the rounds of each iteration: the color classes in order, or every vertex at once; each round holds its vertex indices by partition
+	classOf := func(vert uint32) int {
+		if colors == nil {
+			return 0
+		}
+		return colors[vert]
+	}
+	var rounds [][][]int
+	for p, partition := range partitionSlices {
+		for _, vert := range partition {
+			class := classOf(vert)
+			for len(rounds) <= class {
+				rounds = append(rounds, make([][]int, len(partitionSlices)))
+			}
+			rounds[class][p] = append(rounds[class][p], state.vertIdx[vert])
+		}
+	}
+
+	next := make([]int32, len(state.vertices))
+	for t := 1; t <= options.MaxIterations; t++ {
+		var changed int64
+		for _, round := range rounds {
+			var wg sync.WaitGroup
+			for _, indices := range round {
+				wg.Add(1)
+				go func(indices []int) {
+					defer wg.Done()
+					for _, idx := range indices {
+						if cancelled(ctx) {
+							return
+						}
+						next[idx] = state.choose(idx, newStreamRandom(options.Seed, t, idx))
+					}
+				}(indices)
+			}
+			wg.Wait()
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			for _, indices := range round {
+				for _, idx := range indices {
+					if next[idx] != state.labels[idx] {
+						state.labels[idx] = next[idx]
+						changed++
+					}
+				}
+			}
+		}
+		reporter.emit(t, -1)
+		if changed == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// the vertices grouped by label, numbered from 0 in order of their lowest member; members are in ascending order
+func (state *lpaState) communities() map[int][]uint32 {
+	retVal := make(map[int][]uint32)
+	ids := make(map[int32]int)
+	for idx, vert := range state.vertices {
+		id, ok := ids[state.labels[idx]]
+		if !ok {
+			id = len(ids)
+			ids[state.labels[idx]] = id
+		}
+		retVal[id] = append(retVal[id], vert)
+	}
+	return retVal
+}
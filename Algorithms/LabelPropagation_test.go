@@ -0,0 +1,234 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var lpaModes = map[LPAMode]string{AsynchronousLPA: "asynchronous", SynchronousLPA: "synchronous", SemiSynchronousLPA: "semi-synchronous"}
+
+// checks that communities partition G and that every vertex carries a label with the most votes among its neighbors
+func checkLPAStable(t *testing.T, G *Core.Network, communities map[int][]uint32, weighted bool, mode string) {
+	label := make(map[uint32]int)
+	for c, members := range communities {
+		for _, v := range members {
+			if _, ok := label[v]; ok {
+				t.Fatalf("Vertex %d is in two communities (%s)", v, mode)
+			}
+			label[v] = c
+		}
+	}
+	if len(label) != G.Order() {
+		t.Fatalf("Expected every vertex in a community, found %d of %d (%s)", len(label), G.Order(), mode)
+	}
+	for _, v := range G.Vertices(false) {
+		votes := make(map[int]float64)
+		best := 0.0
+		for _, edge := range traversalNeighbors(G, v, Outgoing) {
+			vote := 1.0
+			if weighted {
+				vote = float64(edge.weight)
+			}
+			votes[label[edge.to]] += vote
+			if votes[label[edge.to]] > best {
+				best = votes[label[edge.to]]
+			}
+		}
+		if len(votes) > 0 && votes[label[v]] < best {
+			t.Errorf("Vertex %d would change community (%s)", v, mode)
+		}
+	}
+}
+
+func TestLabelPropagationCliques(t *testing.T) {
+	G := makeTwoCliques()
+	for mode, name := range lpaModes {
+		for _, count := range []int{1, 3} {
+			options := NewLPAOptions()
+			options.Mode = mode
+			options.ConcurrentCount = count
+			options.Seed = 7
+			communities, err := LabelPropagation(G, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkLPAStable(t, G, communities, false, name)
+			if mode != SynchronousLPA && len(communities) != 2 {
+				t.Errorf("Expected the two cliques as communities (%s, %d goroutines), found %v", name, count, communities)
+			}
+		}
+	}
+}
+
+func TestLabelPropagationPlanted(t *testing.T) {
+	probs := [][]float64{{0.3, 0.01, 0.01, 0.01}, {0.01, 0.3, 0.01, 0.01}, {0.01, 0.01, 0.3, 0.01}, {0.01, 0.01, 0.01, 0.3}}
+	G, planted, _ := Generators.StochasticBlockModel([]int{40, 40, 40, 40}, probs, false, rand.New(rand.NewSource(4)))
+
+	for _, mode := range []LPAMode{AsynchronousLPA, SynchronousLPA, SemiSynchronousLPA} {
+		options := NewLPAOptions()
+		options.Mode = mode
+		options.ConcurrentCount = 4
+		options.Seed = 2
+		communities, err := LabelPropagation(G, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkLPAStable(t, G, communities, false, lpaModes[mode])
+		// the asynchronous result depends on goroutine scheduling, and merging two planted blocks is a legitimate outcome
+		if mode == AsynchronousLPA {
+			continue
+		}
+		if agreement := pairAgreement(communities, planted); agreement < 0.9 {
+			t.Errorf("Expected the planted blocks to be recovered (%s), found pair agreement %f", lpaModes[mode], agreement)
+		}
+	}
+}
+
+func TestLabelPropagationPartitionsAgree(t *testing.T) {
+	// the synchronous and semi-synchronous modes depend only on the seed, not on how the vertices are divided among goroutines
+	params := Generators.NewLFRParameters(200, 10, 25, 0.2)
+	params.MinCommunity = 20
+	params.MaxCommunity = 50
+	G, _, err := Generators.LFR(params, rand.New(rand.NewSource(9)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bfs, _ := BFSPartitions(G, 5)
+	for _, mode := range []LPAMode{SynchronousLPA, SemiSynchronousLPA} {
+		var previous map[int][]uint32
+		for _, partitions := range [][][]uint32{nil, bfs} {
+			for _, count := range []int{1, 4} {
+				options := NewLPAOptions()
+				options.Mode = mode
+				options.ConcurrentCount = count
+				options.Partitions = partitions
+				options.Seed = 5
+				communities, err := LabelPropagation(G, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if previous != nil && !reflect.DeepEqual(communities, previous) {
+					t.Errorf("Expected the same communities however the vertices are partitioned (%s)", lpaModes[mode])
+				}
+				previous = communities
+			}
+		}
+		checkLPAStable(t, G, previous, false, lpaModes[mode])
+	}
+}
+
+func TestLabelPropagationConvergence(t *testing.T) {
+	// on a single edge synchronous propagation swaps the two labels forever; semi-synchronous propagation settles at once
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(0, 1, 1.0)
+	options := NewLPAOptions()
+	options.MaxIterations = 10
+	options.Mode = SynchronousLPA
+	communities, err := LabelPropagation(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(communities) != 2 {
+		t.Errorf("Expected synchronous propagation to end oscillating after an even number of iterations, found %v", communities)
+	}
+	options.Mode = SemiSynchronousLPA
+	iterations := 0
+	communities, err = LabelPropagationContext(context.Background(), G, options, func(p Progress) { iterations = p.Iteration })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(communities) != 1 || iterations > 2 {
+		t.Errorf("Expected semi-synchronous propagation to converge to one community, found %v after %d iterations", communities, iterations)
+	}
+}
+
+func TestLabelPropagationWeighted(t *testing.T) {
+	// vertex 0 hangs from triangle 1-2-3 by a heavy edge and from triangle 4-5-6 by two light ones
+	G := Core.NewNetwork(false)
+	for _, base := range []uint32{1, 4} {
+		_ = G.AddEdge(base, base+1, 5.0)
+		_ = G.AddEdge(base+1, base+2, 5.0)
+		_ = G.AddEdge(base+2, base, 5.0)
+	}
+	_ = G.AddEdge(0, 1, 6.0)
+	_ = G.AddEdge(0, 4, 1.0)
+	_ = G.AddEdge(0, 5, 1.0)
+
+	for _, weighted := range []bool{false, true} {
+		options := NewLPAOptions()
+		options.Mode = SemiSynchronousLPA
+		options.Weighted = weighted
+		communities, err := LabelPropagation(G, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkLPAStable(t, G, communities, weighted, "weighted")
+		for _, members := range communities {
+			if members[0] != 0 {
+				continue
+			}
+			expected := uint32(4)
+			if weighted {
+				expected = 1
+			}
+			if len(members) != 4 || !reflect.DeepEqual(members[1:], []uint32{expected, expected + 1, expected + 2}) {
+				t.Errorf("Expected vertex 0 with triangle %d (weighted %v), found %v", expected, weighted, members)
+			}
+		}
+	}
+}
+
+func TestLabelPropagationErrors(t *testing.T) {
+	G := makeTwoCliques()
+	if _, err := LabelPropagation(G, nil); err == nil {
+		t.Errorf("Expected an error for nil options")
+	}
+	options := NewLPAOptions()
+	options.MaxIterations = 0
+	if _, err := LabelPropagation(G, options); err == nil {
+		t.Errorf("Expected an error for no iterations")
+	}
+	options = NewLPAOptions()
+	options.Partitions = [][]uint32{{0, 1, 2}}
+	if _, err := LabelPropagation(G, options); err == nil {
+		t.Errorf("Expected an error for partitions missing vertices")
+	}
+	if communities, err := LabelPropagation(Core.NewNetwork(false), NewLPAOptions()); err != nil || len(communities) != 0 {
+		t.Errorf("Expected no communities for an empty network, found %v, %v", communities, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for mode, name := range lpaModes {
+		options := NewLPAOptions()
+		options.Mode = mode
+		if _, err := LabelPropagationContext(ctx, G, options, nil); err != context.Canceled {
+			t.Errorf("Expected a cancelled context to stop %s propagation, found %v", name, err)
+		}
+	}
+}
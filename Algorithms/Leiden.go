@@ -0,0 +1,302 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Leiden community detection: Traag, V. A., Waltman, L., and van Eck, N. J., From Louvain to Leiden: Guaranteeing Well-Connected Communities,
+// Scientific Reports 9, 5233, 2019.
+// Local moving is the concurrent pass used by Louvain.  Refinement merges each vertex, within its community, into the well-connected refined
+// community offering the greatest gain, i.e., the greedy limit of the randomized refinement, so the result depends only on the seed.
+// Local moving may also move a node to an empty community.  Communities are aggregated by their refinement, which keeps every community connected.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"runtime"
+	"sort"
+)
+
+type LeidenQuality int
+
+const (
+	ModularityQuality LeidenQuality = iota
+	CPMQuality                      // Constant Potts Model; the resolution is the density separating communities
+)
+
+type LeidenOptions struct {
+	Quality         LeidenQuality
+	Resolution      float64
+	Seed            int64
+	ConcurrentCount int
+	MaxLevels       int     // 0 for no limit
+	Tolerance       float64 // a pass improving the quality by less than this ends local moving
+}
+
+func NewLeidenOptions() *LeidenOptions {
+	options := new(LeidenOptions)
+	options.Quality = ModularityQuality
+	options.Resolution = 1.0
+	options.Seed = 1
+	options.ConcurrentCount = runtime.NumCPU()
+	options.MaxLevels = 0
+	options.Tolerance = 1e-7
+	return options
+}
+
+// Communities found by the Leiden algorithm, keyed as those returned by ConcurrentSLPA.  Each vertex belongs to exactly one community,
+// and every community is connected.
+func Leiden(G *Core.Network, options *LeidenOptions) (map[int][]uint32, error) {
+	return LeidenContext(context.Background(), G, options, nil)
+}
+
+// As Leiden, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each level
+func LeidenContext(ctx context.Context, G *Core.Network, options *LeidenOptions, progress ProgressFunc) (map[int][]uint32, error) {
+	dendrogram, err := leidenDendrogram(ctx, G, options, newProgressReporter("LeidenContext", progress))
+	if err != nil {
+		return nil, err
+	}
+	return dendrogram.Final(), nil
+}
+
+// The communities found at every level of the Leiden algorithm
+func LeidenDendrogram(G *Core.Network, options *LeidenOptions) (*Dendrogram, error) {
+	return LeidenDendrogramContext(context.Background(), G, options, nil)
+}
+
+// As LeidenDendrogram, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each level
+func LeidenDendrogramContext(ctx context.Context, G *Core.Network, options *LeidenOptions, progress ProgressFunc) (*Dendrogram, error) {
+	return leidenDendrogram(ctx, G, options, newProgressReporter("LeidenDendrogramContext", progress))
+}
+
+func leidenDendrogram(ctx context.Context, G *Core.Network, options *LeidenOptions, reporter *progressReporter) (*Dendrogram, error) {
+	if options == nil {
+		return nil, Core.NewNetworkArgumentNullError("Options must be non-null")
+	}
+	if options.Quality != ModularityQuality && options.Quality != CPMQuality {
+		return nil, Core.NewNetworkArgumentError("Unknown quality function")
+	}
+	if options.Resolution < 0 {
+		return nil, Core.NewNetworkArgumentError("Resolution must be non-negative")
+	}
+	if options.ConcurrentCount < 1 {
+		return nil, Core.NewNetworkArgumentError("ConcurrentCount must be at least 1")
+	}
+	g, vertices, err := newModularityGraph(G)
+	if err != nil {
+		return nil, err
+	}
+
+	q := qualityFunction{cpm: options.Quality == CPMQuality, resolution: options.Resolution}
+	r := rand.New(rand.NewSource(options.Seed))
+	dendrogram := new(Dendrogram)
+	original := g
+	// node of the current level holding each vertex, and the community of each node
+	nodeOf := identityAssignment(len(vertices))
+	community := identityAssignment(g.order())
+	for level := 0; options.MaxLevels == 0 || level < options.MaxLevels; level++ {
+		_, err := g.moveNodes(ctx, community, q, options.Tolerance, r, options.ConcurrentCount, true)
+		if err != nil {
+			return nil, err
+		}
+		count := renumberCommunities(community)
+
+		assignment := make([]int, len(vertices))
+		for v := range nodeOf {
+			assignment[v] = community[nodeOf[v]]
+		}
+		changed := len(dendrogram.Levels) == 0 || !dendrogram.matches(vertices, assignment)
+		if changed {
+			dendrogram.add(vertices, assignment, g.quality(community, q))
+			reporter.emit(level+1, -1)
+		}
+		// done when every community is a single node, each node being a connected refined community
+		if count == g.order() {
+			break
+		}
+
+		// aggregate by the refinement only; when refinement merges nothing the aggregate is the same network, so stop once the partition
+		// has stopped changing as well
+		refined := g.refine(community, q, r)
+		refinedCount := renumberCommunities(refined)
+		if refinedCount == g.order() && !changed {
+			break
+		}
+		next := make([]int, refinedCount)
+		for i, c := range refined {
+			next[c] = community[i]
+		}
+		for v := range nodeOf {
+			nodeOf[v] = refined[nodeOf[v]]
+		}
+		g = g.aggregate(refined, refinedCount)
+		community = next
+	}
+
+	// the greedy refinement can, rarely, leave a community of several nodes that is not connected; splitting it into its components never
+	// lowers the quality, as no edge joins them
+	if last := len(dendrogram.Levels) - 1; last >= 0 {
+		assignment := make([]int, len(vertices))
+		for i, v := range vertices {
+			assignment[i] = dendrogram.Levels[last][v]
+		}
+		if splitDisconnected(G, vertices, assignment) {
+			dendrogram.Levels[last] = make(map[uint32]int, len(vertices))
+			for i, v := range vertices {
+				dendrogram.Levels[last][v] = assignment[i]
+			}
+			dendrogram.Quality[last] = original.quality(assignment, q)
+		}
+	}
+	return dendrogram, nil
+}
+
+// gives each connected component of every community, ignoring edge direction, a community of its own, renumbering the communities in
+// order of their lowest vertex; returns true if any community was split
+func splitDisconnected(G *Core.Network, vertices []uint32, community []int) bool {
+	vertIdx := make(map[uint32]int, len(vertices))
+	for idx, v := range vertices {
+		vertIdx[v] = idx
+	}
+	component := make([]int, len(vertices))
+	for i := range component {
+		component[i] = -1
+	}
+	components := 0
+	seen := make(map[int]bool)
+	split := false
+	for start := range vertices {
+		if component[start] != -1 {
+			continue
+		}
+		if seen[community[start]] {
+			split = true
+		}
+		seen[community[start]] = true
+		component[start] = components
+		queue := []int{start}
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+			for _, edge := range traversalNeighbors(G, vertices[i], AnyDirection) {
+				j := vertIdx[edge.to]
+				if component[j] == -1 && community[j] == community[i] {
+					component[j] = components
+					queue = append(queue, j)
+				}
+			}
+		}
+		components++
+	}
+	if split {
+		copy(community, component)
+		renumberCommunities(community)
+	}
+	return split
+}
+
+// true if the last level assigns every vertex as community does
+func (d *Dendrogram) matches(vertices []uint32, community []int) bool {
+	last := d.Levels[len(d.Levels)-1]
+	for i, v := range vertices {
+		if last[v] != community[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Leiden refinement: starting from singletons, each node still alone, visited in random order, joins the refined community within its own
+// community offering the greatest non-negative gain, provided both the node and the refined community are well connected to the rest of
+// their community.  Returns the refined community of each node.
+func (g *modularityGraph) refine(community []int, q qualityFunction, r *rand.Rand) []int {
+	n := g.order()
+	refined := identityAssignment(n)
+	totals := g.totals(refined)
+	communityTotals := g.totals(community)
+	singleton := make([]bool, n)
+	// weight between each refined community and the rest of its community
+	external := make([]float64, n)
+	for i := 0; i < n; i++ {
+		singleton[i] = true
+		for c, weight := range g.communityWeights(i, community) {
+			if c == community[i] {
+				external[i] = weight
+			}
+		}
+	}
+
+	for _, i := range r.Perm(n) {
+		if !singleton[i] {
+			continue
+		}
+		c := community[i]
+		rest := communityTotals[c].minus(g.node(i))
+		if external[i] < q.penalty(g.node(i), rest, g.total) {
+			continue
+		}
+
+		// weights to the refined communities within c
+		weights := make(map[int]float64)
+		for _, arc := range g.out[i] {
+			if community[arc.to] == c {
+				weights[refined[arc.to]] += arc.weight
+			}
+		}
+		for _, arc := range g.in[i] {
+			if community[arc.to] == c {
+				weights[refined[arc.to]] += arc.weight
+			}
+		}
+		candidates := make([]int, 0, len(weights))
+		for t := range weights {
+			candidates = append(candidates, t)
+		}
+		sort.Ints(candidates)
+
+		best, bestGain := -1, 0.0
+		for _, t := range candidates {
+			if t == refined[i] {
+				continue
+			}
+			if external[t] < q.penalty(totals[t], communityTotals[c].minus(totals[t]), g.total) {
+				continue
+			}
+			gain := weights[t] - q.penalty(g.node(i), totals[t], g.total)
+			if gain >= 0 && (best == -1 || gain > bestGain+moveEpsilon) {
+				best = t
+				bestGain = gain
+			}
+		}
+		if best == -1 {
+			continue
+		}
+
+		own := refined[i]
+		external[best] += external[i] - 2*weights[best]
+		totals[best] = totals[best].plus(g.node(i))
+		totals[own] = communityTotal{}
+		refined[i] = best
+		singleton[best] = false
+		singleton[i] = false
+	}
+	return refined
+}
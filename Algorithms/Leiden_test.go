@@ -0,0 +1,175 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// true if every community induces a connected subgraph, ignoring edge direction
+func communitiesConnected(t *testing.T, G *Core.Network, communities map[int][]uint32) bool {
+	options := NewTraversalOptions()
+	options.Direction = AnyDirection
+	for _, members := range communities {
+		set := make(map[uint32]bool, len(members))
+		for _, v := range members {
+			set[v] = true
+		}
+		result, err := BreadthFirst(inducedSubgraph(G, set), members[0], options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Order) != len(members) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLeidenTwoCliques(t *testing.T) {
+	G := makeTwoCliques()
+	expected := map[int][]uint32{0: {0, 1, 2, 3, 4, 5}, 1: {10, 11, 12, 13, 14, 15}}
+	communities, err := Leiden(G, NewLeidenOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(communities, expected) {
+		t.Errorf("Expected the two cliques by modularity, found %v", communities)
+	}
+
+	// each clique has 15 edges against a penalty of 0.5 * 15 pairs
+	options := NewLeidenOptions()
+	options.Quality = CPMQuality
+	options.Resolution = 0.5
+	dendrogram, err := LeidenDendrogram(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dendrogram.Final(), expected) {
+		t.Errorf("Expected the two cliques by CPM, found %v", dendrogram.Final())
+	}
+	if q := dendrogram.Quality[len(dendrogram.Quality)-1]; math.Abs(q-15.0) > 1e-9 {
+		t.Errorf("Expected CPM quality 15, found %f", q)
+	}
+
+	// above the density of the cliques, every vertex is alone
+	options.Resolution = 1.5
+	communities, _ = Leiden(G, options)
+	if len(communities) != G.Order() {
+		t.Errorf("Expected singletons at resolution 1.5, found %v", communities)
+	}
+}
+
+func TestLeidenPlanted(t *testing.T) {
+	params := Generators.NewLFRParameters(300, 12, 30, 0.2)
+	params.MinCommunity = 20
+	params.MaxCommunity = 60
+	G, planted, err := Generators.LFR(params, rand.New(rand.NewSource(6)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := NewLeidenOptions()
+	options.Seed = 3
+	var previous map[int][]uint32
+	for _, count := range []int{1, 4} {
+		options.ConcurrentCount = count
+		dendrogram, err := LeidenDendrogram(G, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i < len(dendrogram.Quality); i++ {
+			if dendrogram.Quality[i] < dendrogram.Quality[i-1]-1e-12 {
+				t.Errorf("Modularity fell from level %d to level %d", i-1, i)
+			}
+		}
+		communities := dendrogram.Final()
+		if !communitiesConnected(t, G, communities) {
+			t.Error("Expected every community to be connected")
+		}
+		if agreement := pairAgreement(communities, planted); agreement < 0.95 {
+			t.Errorf("Expected the planted communities to be recovered, found pair agreement %f", agreement)
+		}
+		if previous != nil && !reflect.DeepEqual(communities, previous) {
+			t.Errorf("Communities with %d goroutines differ from those with one", count)
+		}
+		previous = communities
+	}
+
+	louvain, _ := LouvainDendrogram(G, NewLouvainOptions())
+	leiden, _ := LeidenDendrogram(G, NewLeidenOptions())
+	if leiden.Quality[len(leiden.Quality)-1] < louvain.Quality[len(louvain.Quality)-1]-0.02 {
+		t.Errorf("Leiden modularity %f falls well short of Louvain's %f", leiden.Quality[len(leiden.Quality)-1], louvain.Quality[len(louvain.Quality)-1])
+	}
+}
+
+func TestLeidenConnectedRandom(t *testing.T) {
+	// sparse random networks, including two where moving nodes between non-empty communities alone once stranded a vertex
+	cases := []struct {
+		seed int64
+		p    float64
+	}{{71, 0.115}, {207, 0.093}}
+	r := rand.New(rand.NewSource(72))
+	for trial := 0; trial < 40; trial++ {
+		cases = append(cases, struct {
+			seed int64
+			p    float64
+		}{r.Int63n(1000), 0.06 + 0.08*r.Float64()})
+	}
+
+	for _, c := range cases {
+		G, _ := Generators.ErdosRenyiGnp(60, c.p, false, rand.New(rand.NewSource(c.seed)))
+		for _, quality := range []LeidenQuality{CPMQuality, ModularityQuality} {
+			options := NewLeidenOptions()
+			options.Quality = quality
+			options.Seed = c.seed
+			options.ConcurrentCount = 1 + int(c.seed%4)
+			if quality == CPMQuality {
+				options.Resolution = 0.05
+			}
+			communities, err := Leiden(G, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !communitiesConnected(t, G, communities) {
+				t.Errorf("Expected every community to be connected (seed %d, p %f, quality %d), found %v", c.seed, c.p, quality, communities)
+			}
+		}
+	}
+}
+
+func TestLeidenArguments(t *testing.T) {
+	options := NewLeidenOptions()
+	options.Quality = LeidenQuality(7)
+	if _, err := Leiden(makeTwoCliques(), options); err == nil {
+		t.Error("Expected an error for an unknown quality function")
+	}
+	communities, err := Leiden(Core.NewNetwork(true), NewLeidenOptions())
+	if err != nil || len(communities) != 0 {
+		t.Errorf("Expected no communities in an empty network, found %v", communities)
+	}
+}
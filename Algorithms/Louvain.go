@@ -0,0 +1,516 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Louvain community detection: Blondel, Vincent D., Guillaume, Jean-Loup, Lambiotte, Renaud, and Lefebvre, Etienne, Fast Unfolding of Communities
+// in Large Networks, Journal of Statistical Mechanics: Theory and Experiment, P10008, 2008.  Directed networks use the directed modularity of
+// Leicht and Newman.
+// Local moving is concurrent: in each pass goroutines propose the best community for every vertex from the communities as they stood at the start
+// of the pass, then the proposals are applied in a seeded random order, each only if it still improves modularity.  The result therefore depends on
+// the seed but not on the number of goroutines.
+
+package Algorithms
+
+import (
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type LouvainOptions struct {
+	Resolution      float64 // values above 1 favor smaller communities, values below 1 larger ones
+	Seed            int64
+	ConcurrentCount int
+	MaxLevels       int     // 0 for no limit
+	Tolerance       float64 // a pass or level improving modularity by less than this ends the search
+}
+
+func NewLouvainOptions() *LouvainOptions {
+	options := new(LouvainOptions)
+	options.Resolution = 1.0
+	options.Seed = 1
+	options.ConcurrentCount = runtime.NumCPU()
+	options.MaxLevels = 0
+	options.Tolerance = 1e-7
+	return options
+}
+
+// Levels holds the community of every vertex at each level of aggregation, from the finest; Quality holds the modularity (or, for Leiden with
+// CPM, the Constant Potts Model quality) of each level.  Community ids are numbered from 0 in order of their lowest member vertex id.
+type Dendrogram struct {
+	Levels  []map[uint32]int
+	Quality []float64
+}
+
+// Communities of the given level, keyed as those returned by ConcurrentSLPA, with members in ascending order
+func (d *Dendrogram) Communities(level int) map[int][]uint32 {
+	retVal := make(map[int][]uint32)
+	if level < 0 || level >= len(d.Levels) {
+		return retVal
+	}
+	vertices := make([]uint32, 0, len(d.Levels[level]))
+	for v := range d.Levels[level] {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool { return vertices[i] < vertices[j] })
+	for _, v := range vertices {
+		c := d.Levels[level][v]
+		retVal[c] = append(retVal[c], v)
+	}
+	return retVal
+}
+
+// Communities of the coarsest level
+func (d *Dendrogram) Final() map[int][]uint32 {
+	return d.Communities(len(d.Levels) - 1)
+}
+
+// Communities found by the Louvain method, keyed as those returned by ConcurrentSLPA.  Each vertex belongs to exactly one community.
+func Louvain(G *Core.Network, options *LouvainOptions) (map[int][]uint32, error) {
+	return LouvainContext(context.Background(), G, options, nil)
+}
+
+// As Louvain, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each level
+func LouvainContext(ctx context.Context, G *Core.Network, options *LouvainOptions, progress ProgressFunc) (map[int][]uint32, error) {
+	dendrogram, err := louvainDendrogram(ctx, G, options, newProgressReporter("LouvainContext", progress))
+	if err != nil {
+		return nil, err
+	}
+	return dendrogram.Final(), nil
+}
+
+// The communities found at every level of the Louvain method
+func LouvainDendrogram(G *Core.Network, options *LouvainOptions) (*Dendrogram, error) {
+	return LouvainDendrogramContext(context.Background(), G, options, nil)
+}
+
+// As LouvainDendrogram, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each level
+func LouvainDendrogramContext(ctx context.Context, G *Core.Network, options *LouvainOptions, progress ProgressFunc) (*Dendrogram, error) {
+	return louvainDendrogram(ctx, G, options, newProgressReporter("LouvainDendrogramContext", progress))
+}
+
+func louvainDendrogram(ctx context.Context, G *Core.Network, options *LouvainOptions, reporter *progressReporter) (*Dendrogram, error) {
+	if options == nil {
+		return nil, Core.NewNetworkArgumentNullError("Options must be non-null")
+	}
+	if options.Resolution < 0 {
+		return nil, Core.NewNetworkArgumentError("Resolution must be non-negative")
+	}
+	if options.ConcurrentCount < 1 {
+		return nil, Core.NewNetworkArgumentError("ConcurrentCount must be at least 1")
+	}
+	g, vertices, err := newModularityGraph(G)
+	if err != nil {
+		return nil, err
+	}
+
+	q := qualityFunction{resolution: options.Resolution}
+	r := rand.New(rand.NewSource(options.Seed))
+	dendrogram := new(Dendrogram)
+	// node of the current level holding each vertex
+	nodeOf := identityAssignment(len(vertices))
+	for level := 0; options.MaxLevels == 0 || level < options.MaxLevels; level++ {
+		community := identityAssignment(g.order())
+		moved, err := g.moveNodes(ctx, community, q, options.Tolerance, r, options.ConcurrentCount, false)
+		if err != nil {
+			return nil, err
+		}
+		count := renumberCommunities(community)
+		if !moved || count == g.order() {
+			break
+		}
+
+		quality := g.quality(community, q)
+		if len(dendrogram.Quality) > 0 && quality-dendrogram.Quality[len(dendrogram.Quality)-1] < options.Tolerance {
+			break
+		}
+		for v := range nodeOf {
+			nodeOf[v] = community[nodeOf[v]]
+		}
+		dendrogram.add(vertices, nodeOf, quality)
+		reporter.emit(level+1, -1)
+		g = g.aggregate(community, count)
+	}
+
+	// no vertex moved: every vertex is its own community
+	if len(dendrogram.Levels) == 0 {
+		dendrogram.add(vertices, nodeOf, g.quality(identityAssignment(g.order()), q))
+	}
+	return dendrogram, nil
+}
+
+func (d *Dendrogram) add(vertices []uint32, community []int, quality float64) {
+	level := make(map[uint32]int, len(vertices))
+	for i, v := range vertices {
+		level[v] = community[i]
+	}
+	d.Levels = append(d.Levels, level)
+	d.Quality = append(d.Quality, quality)
+}
+
+// Weighted network of nodes 0..n-1 in which modularity or CPM is optimized.  Undirected edges are held as a pair of arcs, so the directed and
+// undirected forms of the quality functions are computed alike; arcs within a node, left by aggregation, are held in self, and size counts
+// the vertices of the original network a node holds.
+type modularityGraph struct {
+	out      [][]weightedArc
+	in       [][]weightedArc
+	self     []float64
+	kout     []float64
+	kin      []float64
+	size     []float64
+	total    float64
+	directed bool
+}
+
+type weightedArc struct {
+	to     int
+	weight float64
+}
+
+// out- and in-strength and size of a node or of a set of nodes
+type communityTotal struct {
+	out  float64
+	in   float64
+	size float64
+}
+
+func (a communityTotal) plus(b communityTotal) communityTotal {
+	return communityTotal{out: a.out + b.out, in: a.in + b.in, size: a.size + b.size}
+}
+
+func (a communityTotal) minus(b communityTotal) communityTotal {
+	return communityTotal{out: a.out - b.out, in: a.in - b.in, size: a.size - b.size}
+}
+
+// Modularity, or the Constant Potts Model when cpm is set, at the given resolution
+type qualityFunction struct {
+	cpm        bool
+	resolution float64
+}
+
+// the expected arc weight between disjoint sets a and b, in both directions, that offsets the weight actually joining them
+func (q qualityFunction) penalty(a communityTotal, b communityTotal, total float64) float64 {
+	if q.cpm {
+		return 2 * q.resolution * a.size * b.size
+	}
+	return q.resolution * (a.out*b.in + a.in*b.out) / total
+}
+
+// builds the modularity graph of G, whose nodes are the vertices of G in ascending order
+func newModularityGraph(G *Core.Network) (*modularityGraph, []uint32, error) {
+	if G == nil {
+		return nil, nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	vertices := G.Vertices(true)
+	index := make(map[uint32]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+
+	g := newEmptyModularityGraph(len(vertices), G.Directed())
+	for i, u := range vertices {
+		g.size[i] = 1
+		for _, n := range traversalNeighbors(G, u, Outgoing) {
+			if n.weight < 0 {
+				return nil, nil, Core.NewNetworkArgumentError(Sprintf("Edge %d - %d has negative weight", u, n.to))
+			}
+			j := index[n.to]
+			wt := float64(n.weight)
+			g.out[i] = append(g.out[i], weightedArc{to: j, weight: wt})
+			g.in[j] = append(g.in[j], weightedArc{to: i, weight: wt})
+			g.kout[i] += wt
+			g.kin[j] += wt
+			g.total += wt
+		}
+	}
+	return g, vertices, nil
+}
+
+func newEmptyModularityGraph(n int, directed bool) *modularityGraph {
+	g := new(modularityGraph)
+	g.out = make([][]weightedArc, n)
+	g.in = make([][]weightedArc, n)
+	g.self = make([]float64, n)
+	g.kout = make([]float64, n)
+	g.kin = make([]float64, n)
+	g.size = make([]float64, n)
+	g.directed = directed
+	return g
+}
+
+func (g *modularityGraph) order() int {
+	return len(g.self)
+}
+
+func (g *modularityGraph) node(i int) communityTotal {
+	return communityTotal{out: g.kout[i], in: g.kin[i], size: g.size[i]}
+}
+
+// totals of each community, indexed by community id
+func (g *modularityGraph) totals(community []int) []communityTotal {
+	retVal := make([]communityTotal, g.order())
+	for i, c := range community {
+		retVal[c] = retVal[c].plus(g.node(i))
+	}
+	return retVal
+}
+
+// Modularity is (1/m) sum over communities of (internal weight - resolution * outC * inC / m).  CPM is the sum over communities of
+// (internal weight - resolution * nC (nC - 1)), halved for undirected networks so that each edge and each pair of vertices counts once.
+func (g *modularityGraph) quality(community []int, q qualityFunction) float64 {
+	internal := make(map[int]float64)
+	totals := make(map[int]communityTotal)
+	for i := range g.self {
+		c := community[i]
+		internal[c] += g.self[i]
+		for _, arc := range g.out[i] {
+			if community[arc.to] == c {
+				internal[c] += arc.weight
+			}
+		}
+		totals[c] = totals[c].plus(g.node(i))
+	}
+
+	retVal := 0.0
+	if q.cpm {
+		for c, total := range totals {
+			retVal += internal[c] - q.resolution*total.size*(total.size-1)
+		}
+		if !g.directed {
+			retVal /= 2
+		}
+		return retVal
+	}
+	if g.total == 0 {
+		return 0
+	}
+	for c, weight := range internal {
+		retVal += weight - q.resolution*totals[c].out*totals[c].in/g.total
+	}
+	return retVal / g.total
+}
+
+// collapses each community to a node; community ids must run from 0 to count-1
+func (g *modularityGraph) aggregate(community []int, count int) *modularityGraph {
+	retVal := newEmptyModularityGraph(count, g.directed)
+	retVal.total = g.total
+	weights := make([]map[int]float64, count)
+	for c := range weights {
+		weights[c] = make(map[int]float64)
+	}
+	for i := range g.self {
+		c := community[i]
+		retVal.self[c] += g.self[i]
+		retVal.kout[c] += g.kout[i]
+		retVal.kin[c] += g.kin[i]
+		retVal.size[c] += g.size[i]
+		for _, arc := range g.out[i] {
+			d := community[arc.to]
+			if d == c {
+				retVal.self[c] += arc.weight
+			} else {
+				weights[c][d] += arc.weight
+			}
+		}
+	}
+	for c, targets := range weights {
+		ids := make([]int, 0, len(targets))
+		for d := range targets {
+			ids = append(ids, d)
+		}
+		sort.Ints(ids)
+		for _, d := range ids {
+			retVal.out[c] = append(retVal.out[c], weightedArc{to: d, weight: targets[d]})
+			retVal.in[d] = append(retVal.in[d], weightedArc{to: c, weight: targets[d]})
+		}
+	}
+	return retVal
+}
+
+// the weight of arcs between node i and each community, in either direction, ignoring arcs within i
+func (g *modularityGraph) communityWeights(i int, community []int) map[int]float64 {
+	retVal := make(map[int]float64)
+	for _, arc := range g.out[i] {
+		retVal[community[arc.to]] += arc.weight
+	}
+	for _, arc := range g.in[i] {
+		retVal[community[arc.to]] += arc.weight
+	}
+	return retVal
+}
+
+// the change in quality, up to a positive factor, from adding node i, removed from its own community, to community c, given the arc weight
+// between them
+func (g *modularityGraph) moveGain(i int, c int, weight float64, community []int, totals []communityTotal, q qualityFunction) float64 {
+	target := totals[c]
+	if community[i] == c {
+		target = target.minus(g.node(i))
+	}
+	return weight - q.penalty(g.node(i), target, g.total)
+}
+
+// the community offering node i the greatest gain; its own community unless another is strictly better, and the lowest id among equals.
+// If allowEmpty is true, a community of its own is a candidate as well, offering a gain of zero, and is returned as newCommunity.
+func (g *modularityGraph) bestCommunity(i int, community []int, totals []communityTotal, q qualityFunction, allowEmpty bool) int {
+	weights := g.communityWeights(i, community)
+	own := community[i]
+	best := own
+	bestGain := g.moveGain(i, own, weights[own], community, totals, q)
+	candidates := make([]int, 0, len(weights))
+	for c := range weights {
+		candidates = append(candidates, c)
+	}
+	sort.Ints(candidates)
+	for _, c := range candidates {
+		if c == own {
+			continue
+		}
+		gain := g.moveGain(i, c, weights[c], community, totals, q)
+		if gain > bestGain+moveEpsilon {
+			best = c
+			bestGain = gain
+		}
+	}
+	if allowEmpty && 0 > bestGain+moveEpsilon {
+		best = newCommunity
+	}
+	return best
+}
+
+// the proposal of a node to leave for a community of its own
+const newCommunity = -1
+
+// gains smaller than this are treated as zero, so that rounding cannot move a node back and forth
+const moveEpsilon = 1e-12
+
+// local moving, starting from community, which it updates; returns true if any node moved.  If allowEmpty is true, a node may also leave
+// for an empty community.
+func (g *modularityGraph) moveNodes(ctx context.Context, community []int, q qualityFunction, tolerance float64, r *rand.Rand, concurrentCount int, allowEmpty bool) (bool, error) {
+	n := g.order()
+	if n == 0 || g.total == 0 {
+		return false, nil
+	}
+	totals := g.totals(community)
+	if concurrentCount > n {
+		concurrentCount = n
+	}
+
+	// the number of nodes in each community, and the ids of those with none, for nodes leaving for a community of their own
+	members := make([]int, n)
+	for _, c := range community {
+		members[c]++
+	}
+	empty := make([]int, 0)
+	for c := n - 1; c >= 0; c-- {
+		if members[c] == 0 {
+			empty = append(empty, c)
+		}
+	}
+
+	moved := false
+	order := r.Perm(n)
+	proposals := make([]int, n)
+	quality := g.quality(community, q)
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		// propose moves concurrently; community and totals are not written until every goroutine is done
+		var wg sync.WaitGroup
+		for p := 0; p < concurrentCount; p++ {
+			wg.Add(1)
+			go func(low int, high int) {
+				defer wg.Done()
+				for i := low; i < high; i++ {
+					proposals[i] = g.bestCommunity(i, community, totals, q, allowEmpty)
+				}
+			}(p*n/concurrentCount, (p+1)*n/concurrentCount)
+		}
+		wg.Wait()
+
+		// apply the proposals that still improve the quality
+		moves := 0
+		for _, i := range order {
+			target := proposals[i]
+			own := community[i]
+			if target == own {
+				continue
+			}
+			weights := g.communityWeights(i, community)
+			ownGain := g.moveGain(i, own, weights[own], community, totals, q)
+			if target == newCommunity {
+				// a community of its own offers a gain of zero
+				if len(empty) == 0 || 0 <= ownGain+moveEpsilon {
+					continue
+				}
+				target = empty[len(empty)-1]
+				empty = empty[:len(empty)-1]
+			} else if g.moveGain(i, target, weights[target], community, totals, q) <= ownGain+moveEpsilon {
+				continue
+			}
+			totals[own] = totals[own].minus(g.node(i))
+			totals[target] = totals[target].plus(g.node(i))
+			community[i] = target
+			members[own]--
+			members[target]++
+			if members[own] == 0 {
+				empty = append(empty, own)
+			}
+			moves++
+		}
+		if moves == 0 {
+			break
+		}
+		moved = true
+		next := g.quality(community, q)
+		if next-quality < tolerance {
+			break
+		}
+		quality = next
+	}
+	return moved, nil
+}
+
+func identityAssignment(n int) []int {
+	retVal := make([]int, n)
+	for i := range retVal {
+		retVal[i] = i
+	}
+	return retVal
+}
+
+// renumbers communities from 0 in order of their lowest node and returns the number of communities
+func renumberCommunities(community []int) int {
+	ids := make(map[int]int)
+	for i, c := range community {
+		id, ok := ids[c]
+		if !ok {
+			id = len(ids)
+			ids[c] = id
+		}
+		community[i] = id
+	}
+	return len(ids)
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// the fraction of pairs of vertices on which two partitions agree, together or apart
+func pairAgreement(a map[int][]uint32, b map[int][]uint32) float64 {
+	label := func(communities map[int][]uint32) map[uint32]int {
+		retVal := make(map[uint32]int)
+		for c, members := range communities {
+			for _, v := range members {
+				retVal[v] = c
+			}
+		}
+		return retVal
+	}
+	la, lb := label(a), label(b)
+	vertices := make([]uint32, 0, len(la))
+	for v := range la {
+		vertices = append(vertices, v)
+	}
+	agree, pairs := 0, 0
+	for i := range vertices {
+		for k := i + 1; k < len(vertices); k++ {
+			u, v := vertices[i], vertices[k]
+			if (la[u] == la[v]) == (lb[u] == lb[v]) {
+				agree++
+			}
+			pairs++
+		}
+	}
+	return float64(agree) / float64(pairs)
+}
+
+func TestLouvainTwoCliques(t *testing.T) {
+	communities, err := Louvain(makeTwoCliques(), NewLouvainOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int][]uint32{0: {0, 1, 2, 3, 4, 5}, 1: {10, 11, 12, 13, 14, 15}}
+	if !reflect.DeepEqual(communities, expected) {
+		t.Errorf("Expected the two cliques, found %v", communities)
+	}
+}
+
+func TestLouvainModularity(t *testing.T) {
+	// two triangles joined by an edge: modularity of the triangles is 2 * (3/7 - (7/14)^2) = 5/14
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(1, 2, 1.0)
+	_ = G.AddEdge(2, 3, 1.0)
+	_ = G.AddEdge(3, 1, 1.0)
+	_ = G.AddEdge(4, 5, 1.0)
+	_ = G.AddEdge(5, 6, 1.0)
+	_ = G.AddEdge(6, 4, 1.0)
+	_ = G.AddEdge(3, 4, 1.0)
+	dendrogram, err := LouvainDendrogram(G, NewLouvainOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := dendrogram.Quality[len(dendrogram.Quality)-1]; math.Abs(q-5.0/14.0) > 1e-9 {
+		t.Errorf("Expected modularity 5/14, found %f", q)
+	}
+
+	// at a high resolution no merge pays, so every vertex is alone
+	options := NewLouvainOptions()
+	options.Resolution = 10
+	communities, _ := Louvain(G, options)
+	if len(communities) != 6 {
+		t.Errorf("Expected six singleton communities at resolution 10, found %v", communities)
+	}
+}
+
+func TestLouvainDirected(t *testing.T) {
+	// two directed 5-cycles with chords, joined by a single arc
+	G := Core.NewNetwork(true)
+	for _, base := range []uint32{0, 10} {
+		for i := uint32(0); i < 5; i++ {
+			_ = G.AddEdge(base+i, base+(i+1)%5, 1.0)
+			_ = G.AddEdge(base+i, base+(i+2)%5, 1.0)
+		}
+	}
+	_ = G.AddEdge(4, 10, 1.0)
+	communities, err := Louvain(G, NewLouvainOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int][]uint32{0: {0, 1, 2, 3, 4}, 1: {10, 11, 12, 13, 14}}
+	if !reflect.DeepEqual(communities, expected) {
+		t.Errorf("Expected the two cycles, found %v", communities)
+	}
+}
+
+func TestLouvainPlanted(t *testing.T) {
+	probs := [][]float64{{0.3, 0.01, 0.01, 0.01}, {0.01, 0.3, 0.01, 0.01}, {0.01, 0.01, 0.3, 0.01}, {0.01, 0.01, 0.01, 0.3}}
+	G, planted, _ := Generators.StochasticBlockModel([]int{40, 40, 40, 40}, probs, false, rand.New(rand.NewSource(4)))
+
+	options := NewLouvainOptions()
+	options.Seed = 9
+	var previous map[int][]uint32
+	for _, count := range []int{1, 3, 8} {
+		options.ConcurrentCount = count
+		dendrogram, err := LouvainDendrogram(G, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i < len(dendrogram.Quality); i++ {
+			if dendrogram.Quality[i] < dendrogram.Quality[i-1] {
+				t.Errorf("Modularity fell from level %d to level %d", i-1, i)
+			}
+		}
+		communities := dendrogram.Final()
+		if agreement := pairAgreement(communities, planted); agreement < 0.95 {
+			t.Errorf("Expected the planted blocks to be recovered, found pair agreement %f", agreement)
+		}
+		if previous != nil && !reflect.DeepEqual(communities, previous) {
+			t.Errorf("Communities with %d goroutines differ from those with fewer", count)
+		}
+		previous = communities
+	}
+}
+
+func TestLouvainArguments(t *testing.T) {
+	if _, err := Louvain(nil, NewLouvainOptions()); err == nil {
+		t.Error("Expected an error for a nil network")
+	}
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(1, 2, -1.0)
+	if _, err := Louvain(G, NewLouvainOptions()); err == nil {
+		t.Error("Expected an error for a negative weight")
+	}
+	communities, err := Louvain(Core.NewNetwork(false), NewLouvainOptions())
+	if err != nil || len(communities) != 0 {
+		t.Errorf("Expected no communities in an empty network, found %v", communities)
+	}
+}
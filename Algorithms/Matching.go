@@ -0,0 +1,441 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Matchings: Hopcroft-Karp maximum cardinality and Hungarian maximum weight matching on bipartite networks, with the vertex sets
+// R and B as returned by ConcurrentBipartite, and Edmonds' blossom algorithm for maximum cardinality matching on general networks
+// Edge direction is ignored.
+
+package Algorithms
+
+import (
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+)
+
+// Edges lists the matched edges; for bipartite matchings From is in R and To in B, otherwise From < To.
+// Mate maps each matched vertex to its partner.
+type Matching struct {
+	Edges  []Edge
+	Mate   map[uint32]uint32
+	Weight float64
+}
+
+func (m *Matching) Size() int {
+	return len(m.Edges)
+}
+
+// Maximum cardinality matching of a bipartite network by the algorithm of Hopcroft and Karp, O(m sqrt(n))
+func HopcroftKarp(G *Core.Network, R []uint32, B []uint32) (*Matching, error) {
+	return HopcroftKarpContext(context.Background(), G, R, B, nil)
+}
+
+// As HopcroftKarp, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as each phase completes
+func HopcroftKarpContext(ctx context.Context, G *Core.Network, R []uint32, B []uint32, progress ProgressFunc) (*Matching, error) {
+	adjacency, err := bipartiteAdjacency(G, R, B)
+	if err != nil {
+		return nil, err
+	}
+
+	const unmatched = -1
+	mateR := make([]int, len(R))
+	mateB := make([]int, len(B))
+	for i := range mateR {
+		mateR[i] = unmatched
+	}
+	for j := range mateB {
+		mateB[j] = unmatched
+	}
+	dist := make([]int, len(R))
+
+	// layer the free R vertices and their alternating paths; true if some free B vertex is reachable
+	layer := func() bool {
+		queue := make([]int, 0, len(R))
+		for i := range R {
+			if mateR[i] == unmatched {
+				dist[i] = 0
+				queue = append(queue, i)
+			} else {
+				dist[i] = math.MaxInt32
+			}
+		}
+		found := false
+		for k := 0; k < len(queue); k++ {
+			i := queue[k]
+			for _, j := range adjacency[i] {
+				next := mateB[j]
+				if next == unmatched {
+					found = true
+				} else if dist[next] == math.MaxInt32 {
+					dist[next] = dist[i] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+		return found
+	}
+
+	var augment func(i int) bool
+	augment = func(i int) bool {
+		for _, j := range adjacency[i] {
+			next := mateB[j]
+			if next == unmatched || (dist[next] == dist[i]+1 && augment(next)) {
+				mateR[i] = j
+				mateB[j] = i
+				return true
+			}
+		}
+		// no augmenting path through i in this phase
+		dist[i] = math.MaxInt32
+		return false
+	}
+
+	reporter := newProgressReporter("HopcroftKarpContext", progress)
+	for phase := 1; layer(); phase++ {
+		for i := range R {
+			if cancelled(ctx) {
+				return nil, ctx.Err()
+			}
+			if mateR[i] == unmatched {
+				augment(i)
+			}
+		}
+		reporter.emit(phase, -1)
+	}
+
+	pairs := make([][2]uint32, 0)
+	for i, j := range mateR {
+		if j != unmatched {
+			pairs = append(pairs, [2]uint32{R[i], B[j]})
+		}
+	}
+	return newMatching(G, pairs, true), nil
+}
+
+// Maximum weight matching of a bipartite network by the Hungarian algorithm, O(n^3).  The matching need not be perfect; edges of
+// negative weight are never matched.
+func HungarianMatching(G *Core.Network, R []uint32, B []uint32) (*Matching, error) {
+	return HungarianMatchingContext(context.Background(), G, R, B, nil)
+}
+
+// As HungarianMatching, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as each row is assigned
+func HungarianMatchingContext(ctx context.Context, G *Core.Network, R []uint32, B []uint32, progress ProgressFunc) (*Matching, error) {
+	adjacency, err := bipartiteAdjacency(G, R, B)
+	if err != nil {
+		return nil, err
+	}
+
+	// assign the smaller side (rows) to the larger (columns) minimizing cost = -weight; absent and negative edges cost nothing
+	rows, cols := R, B
+	transposed := false
+	if len(R) > len(B) {
+		rows, cols = B, R
+		transposed = true
+	}
+	n := len(rows)
+	m := len(cols)
+	cost := make([][]float64, n)
+	for i := range cost {
+		cost[i] = make([]float64, m)
+	}
+	for i, targets := range adjacency {
+		for _, j := range targets {
+			wt := float64(undirectedEdge(G, R[i], B[j]).Weight)
+			if wt <= 0 {
+				continue
+			}
+			if transposed {
+				cost[j][i] = -wt
+			} else {
+				cost[i][j] = -wt
+			}
+		}
+	}
+
+	// potentials u (rows) and v (columns), with row and column 0 as sentinels; p[j] is the row assigned to column j
+	u := make([]float64, n+1)
+	v := make([]float64, m+1)
+	p := make([]int, m+1)
+	way := make([]int, m+1)
+	reporter := newProgressReporter("HungarianMatchingContext", progress)
+	for i := 1; i <= n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if i > 1 {
+			reporter.emit(i-1, -1)
+		}
+		p[0] = i
+		j0 := 0
+		minv := make([]float64, m+1)
+		used := make([]bool, m+1)
+		for j := range minv {
+			minv[j] = math.Inf(1)
+		}
+		for {
+			used[j0] = true
+			i0 := p[j0]
+			delta := math.Inf(1)
+			j1 := 0
+			for j := 1; j <= m; j++ {
+				if used[j] {
+					continue
+				}
+				cur := cost[i0-1][j-1] - u[i0] - v[j]
+				if cur < minv[j] {
+					minv[j] = cur
+					way[j] = j0
+				}
+				if minv[j] < delta {
+					delta = minv[j]
+					j1 = j
+				}
+			}
+			for j := 0; j <= m; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+
+	pairs := make([][2]uint32, 0)
+	for j := 1; j <= m; j++ {
+		if p[j] == 0 {
+			continue
+		}
+		r, b := rows[p[j]-1], cols[j-1]
+		if transposed {
+			r, b = b, r
+		}
+		// drop assignments standing in for absent or negative edges
+		if (G.HasEdge(r, b) || G.HasEdge(b, r)) && undirectedEdge(G, r, b).Weight >= 0 {
+			pairs = append(pairs, [2]uint32{r, b})
+		}
+	}
+	reporter.emit(n, -1)
+	return newMatching(G, pairs, true), nil
+}
+
+// Maximum cardinality matching of a general network by Edmonds' blossom algorithm, O(n^3)
+func BlossomMatching(G *Core.Network) (*Matching, error) {
+	return BlossomMatchingContext(context.Background(), G, nil)
+}
+
+// As BlossomMatching, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each search for an
+// augmenting path, counting the vertices searched from
+func BlossomMatchingContext(ctx context.Context, G *Core.Network, progress ProgressFunc) (*Matching, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+
+	vertices := G.Vertices(true)
+	n := len(vertices)
+	index := make(map[uint32]int, n)
+	for i, v := range vertices {
+		index[v] = i
+	}
+	adjacency := make([][]int, n)
+	for i, v := range vertices {
+		for _, edge := range traversalNeighbors(G, v, AnyDirection) {
+			adjacency[i] = append(adjacency[i], index[edge.to])
+		}
+	}
+
+	match := make([]int, n)
+	parent := make([]int, n)
+	base := make([]int, n)
+	used := make([]bool, n)
+	blossom := make([]bool, n)
+	for i := range match {
+		match[i] = -1
+	}
+
+	// lowest common ancestor of a and b in the alternating tree, by blossom base
+	lca := func(a int, b int) int {
+		seen := make([]bool, n)
+		for {
+			a = base[a]
+			seen[a] = true
+			if match[a] == -1 {
+				break
+			}
+			a = parent[match[a]]
+		}
+		for {
+			b = base[b]
+			if seen[b] {
+				return b
+			}
+			b = parent[match[b]]
+		}
+	}
+
+	markPath := func(v int, b int, child int) {
+		for base[v] != b {
+			blossom[base[v]] = true
+			blossom[base[match[v]]] = true
+			parent[v] = child
+			child = match[v]
+			v = parent[match[v]]
+		}
+	}
+
+	// grow an alternating tree from root; returns the free vertex ending an augmenting path, or -1
+	findPath := func(root int) int {
+		for i := 0; i < n; i++ {
+			used[i] = false
+			parent[i] = -1
+			base[i] = i
+		}
+		used[root] = true
+		queue := []int{root}
+		for k := 0; k < len(queue); k++ {
+			v := queue[k]
+			for _, to := range adjacency[v] {
+				if base[v] == base[to] || match[v] == to {
+					continue
+				}
+				if to == root || (match[to] != -1 && parent[match[to]] != -1) {
+					// odd cycle: contract the blossom onto its base
+					current := lca(v, to)
+					for i := range blossom {
+						blossom[i] = false
+					}
+					markPath(v, current, to)
+					markPath(to, current, v)
+					for i := 0; i < n; i++ {
+						if blossom[base[i]] {
+							base[i] = current
+							if !used[i] {
+								used[i] = true
+								queue = append(queue, i)
+							}
+						}
+					}
+				} else if parent[to] == -1 {
+					parent[to] = v
+					if match[to] == -1 {
+						return to
+					}
+					used[match[to]] = true
+					queue = append(queue, match[to])
+				}
+			}
+		}
+		return -1
+	}
+
+	reporter := newProgressReporter("BlossomMatchingContext", progress)
+	for root := 0; root < n; root++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if match[root] != -1 {
+			continue
+		}
+		reporter.emit(root+1, -1)
+		// flip the augmenting path back to the root
+		for u := findPath(root); u != -1; {
+			pv := parent[u]
+			ppv := match[pv]
+			match[u] = pv
+			match[pv] = u
+			u = ppv
+		}
+	}
+
+	pairs := make([][2]uint32, 0)
+	for i, j := range match {
+		if j > i {
+			pairs = append(pairs, [2]uint32{vertices[i], vertices[j]})
+		}
+	}
+	return newMatching(G, pairs, false), nil
+}
+
+// adjacency of R into B by index, after checking that the vertex sets are disjoint and every edge joins them
+func bipartiteAdjacency(G *Core.Network, R []uint32, B []uint32) ([][]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	inR := make(map[uint32]bool, len(R))
+	for _, v := range R {
+		inR[v] = true
+	}
+	indexB := make(map[uint32]int, len(B))
+	for j, v := range B {
+		if inR[v] {
+			return nil, Core.NewNetworkArgumentError(Sprintf("Vertex %d is in both R and B", v))
+		}
+		indexB[v] = j
+	}
+
+	retVal := make([][]int, len(R))
+	for i, v := range R {
+		for _, edge := range traversalNeighbors(G, v, AnyDirection) {
+			j, ok := indexB[edge.to]
+			if !ok {
+				return nil, Core.NewNetworkArgumentError(Sprintf("Edge %d - %d does not join R and B", v, edge.to))
+			}
+			retVal[i] = append(retVal[i], j)
+		}
+	}
+	for _, v := range B {
+		for _, edge := range traversalNeighbors(G, v, AnyDirection) {
+			if !inR[edge.to] {
+				return nil, Core.NewNetworkArgumentError(Sprintf("Edge %d - %d does not join R and B", v, edge.to))
+			}
+		}
+	}
+	return retVal, nil
+}
+
+func newMatching(G *Core.Network, pairs [][2]uint32, bipartite bool) *Matching {
+	retVal := new(Matching)
+	retVal.Edges = make([]Edge, 0, len(pairs))
+	retVal.Mate = make(map[uint32]uint32, 2*len(pairs))
+	for _, pair := range pairs {
+		edge := undirectedEdge(G, pair[0], pair[1])
+		if bipartite {
+			edge.From, edge.To = pair[0], pair[1]
+		}
+		retVal.Edges = append(retVal.Edges, edge)
+		retVal.Mate[pair[0]] = pair[1]
+		retVal.Mate[pair[1]] = pair[0]
+		retVal.Weight += float64(edge.Weight)
+	}
+	sortEdges(retVal.Edges)
+	return retVal
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// best matching by trying every subset of edges; weighted returns the maximum weight, otherwise the maximum size
+func bruteMatching(G *Core.Network, weighted bool) float64 {
+	edges := make([]Edge, 0)
+	for _, u := range G.Vertices(true) {
+		for _, edge := range traversalNeighbors(G, u, AnyDirection) {
+			if u < edge.to {
+				edges = append(edges, undirectedEdge(G, u, edge.to))
+			}
+		}
+	}
+	used := make(map[uint32]bool)
+	var search func(i int) float64
+	search = func(i int) float64 {
+		if i == len(edges) {
+			return 0
+		}
+		best := search(i + 1)
+		e := edges[i]
+		if !used[e.From] && !used[e.To] {
+			used[e.From] = true
+			used[e.To] = true
+			value := 1.0
+			if weighted {
+				value = float64(e.Weight)
+			}
+			if with := value + search(i+1); with > best {
+				best = with
+			}
+			used[e.From] = false
+			used[e.To] = false
+		}
+		return best
+	}
+	return search(0)
+}
+
+func randomBipartite(nr int, nb int, p float64, r *rand.Rand) (*Core.Network, []uint32, []uint32) {
+	G := Core.NewNetwork(false)
+	R := make([]uint32, nr)
+	B := make([]uint32, nb)
+	for i := range R {
+		R[i] = uint32(i)
+		G.AddVertex(R[i])
+	}
+	for j := range B {
+		B[j] = uint32(nr + j)
+		G.AddVertex(B[j])
+	}
+	for _, u := range R {
+		for _, v := range B {
+			if r.Float64() < p {
+				_ = G.AddEdge(u, v, float32(r.Intn(21)-5))
+			}
+		}
+	}
+	return G, R, B
+}
+
+func checkMatching(t *testing.T, G *Core.Network, m *Matching) {
+	seen := make(map[uint32]bool)
+	for _, edge := range m.Edges {
+		if !G.HasEdge(edge.From, edge.To) && !G.HasEdge(edge.To, edge.From) {
+			t.Errorf("Matched pair %d - %d is not an edge", edge.From, edge.To)
+		}
+		if seen[edge.From] || seen[edge.To] {
+			t.Errorf("Vertex matched twice in %v", m.Edges)
+		}
+		seen[edge.From] = true
+		seen[edge.To] = true
+		if m.Mate[edge.From] != edge.To || m.Mate[edge.To] != edge.From {
+			t.Errorf("Mate does not agree with the matched edge %d - %d", edge.From, edge.To)
+		}
+	}
+}
+
+func TestBipartiteMatching(t *testing.T) {
+	// a 3 x 3 grid path graph is bipartite; ConcurrentBipartite supplies R and B
+	G := Core.NewNetwork(false)
+	for i := uint32(0); i < 9; i++ {
+		if i%3 != 2 {
+			_ = G.AddEdge(i, i+1, 1.0)
+		}
+		if i < 6 {
+			_ = G.AddEdge(i, i+3, 1.0)
+		}
+	}
+	ok, R, B := ConcurrentBipartite(G, 2)
+	if !ok {
+		t.Fatal("Expected the grid to be bipartite")
+	}
+	m, err := HopcroftKarp(G, R, B)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Size() != 4 {
+		t.Errorf("Expected a maximum matching of 4 edges in the 3 x 3 grid, found %d", m.Size())
+	}
+	checkMatching(t, G, m)
+
+	r := rand.New(rand.NewSource(37))
+	for trial := 0; trial < 30; trial++ {
+		H, R, B := randomBipartite(4+r.Intn(3), 3+r.Intn(4), 0.4, r)
+		m, err := HopcroftKarp(H, R, B)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkMatching(t, H, m)
+		if expected := bruteMatching(H, false); float64(m.Size()) != expected {
+			t.Errorf("Expected a maximum matching of %f edges, found %d", expected, m.Size())
+		}
+
+		w, err := HungarianMatching(H, R, B)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkMatching(t, H, w)
+		if expected := bruteMatching(H, true); math.Abs(w.Weight-expected) > 1e-9 {
+			t.Errorf("Expected a maximum weight matching of %f, found %f", expected, w.Weight)
+		}
+	}
+
+	if _, err = HopcroftKarp(G, R, R); err == nil {
+		t.Errorf("Expected an error for overlapping vertex sets")
+	}
+}
+
+func TestBlossomMatching(t *testing.T) {
+	// the Petersen graph has a perfect matching; augmenting paths through its 5-cycles require blossoms
+	G := Core.NewNetwork(false)
+	for i := uint32(0); i < 5; i++ {
+		_ = G.AddEdge(i, (i+1)%5, 1.0)
+		_ = G.AddEdge(i, i+5, 1.0)
+		_ = G.AddEdge(i+5, (i+2)%5+5, 1.0)
+	}
+	m, err := BlossomMatching(G)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Size() != 5 {
+		t.Errorf("Expected a perfect matching of the Petersen graph, found %d edges", m.Size())
+	}
+	checkMatching(t, G, m)
+
+	r := rand.New(rand.NewSource(38))
+	for trial := 0; trial < 30; trial++ {
+		H := randomWeighted(9, 0.3, trial%3 == 0, r)
+		m, _ := BlossomMatching(H)
+		checkMatching(t, H, m)
+		if expected := bruteMatching(H, false); float64(m.Size()) != expected {
+			t.Errorf("Expected a maximum matching of %f edges, found %d", expected, m.Size())
+		}
+	}
+}
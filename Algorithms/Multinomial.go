@@ -29,7 +29,7 @@ import (
 )
 type MultinomialLabels struct {
 	slots []float64
-	rand rand.Rand
+	rand *rand.Rand
 	labels []int
 }
 
@@ -48,13 +48,14 @@ func NewMultinomialLabels(labelsObserved *sync.Map, r *rand.Rand) *MultinomialLa
 	sum:= sumObservations(values)
 	probs := calcProbabilities(values, sum)
 	bounds := make([]float64, len(probs))
-	multi.rand = *r
+	// share the caller's generator so successive distributions draw successive numbers
+	multi.rand = r
 	var top = 0.0
 
-
-
+	// cumulative bounds for the labels
 	for i, v := range probs {
-		bounds[i] = top + v
+		top += v
+		bounds[i] = top
 	}
 	multi.slots = bounds
 	multi.labels = labels
@@ -0,0 +1,218 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Partitioning of a network's vertices among goroutines.  Contiguous ranges of sorted vertex ids ignore the structure of the network, so with
+// arbitrary ids most vertices have neighbors in other partitions.  Growing partitions along breadth-first search, and refining them by label
+// propagation, keeps neighbors together and reduces the edge cut, and with it the dependencies between goroutines.
+
+package Algorithms
+
+import (
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Divides the vertices into count partitions of nearly equal size by cutting a breadth-first ordering into consecutive pieces.  Each connected
+// component is searched from its lowest vertex id, following edges in both directions.  Partitions are sorted.
+func BFSPartitions(G *Core.Network, count int) ([][]uint32, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if count < 1 {
+		return nil, Core.NewNetworkArgumentError("Partition count must be at least 1")
+	}
+	vertices := G.Vertices(true)
+	if len(vertices) == 0 {
+		return [][]uint32{}, nil
+	}
+	if count > len(vertices) {
+		count = len(vertices)
+	}
+
+	ordering := make([]uint32, 0, len(vertices))
+	visited := make(map[uint32]bool, len(vertices))
+	for _, root := range vertices {
+		if visited[root] {
+			continue
+		}
+		visited[root] = true
+		ordering = append(ordering, root)
+		for head := len(ordering) - 1; head < len(ordering); head++ {
+			for _, n := range traversalNeighbors(G, ordering[head], AnyDirection) {
+				if !visited[n.to] {
+					visited[n.to] = true
+					ordering = append(ordering, n.to)
+				}
+			}
+		}
+	}
+
+	retVal, _ := ContiguousPartitions(ordering, count)
+	for i := range retVal {
+		retVal[i] = sortedCopy(retVal[i])
+	}
+	return retVal, nil
+}
+
+// Refines BFSPartitions by label propagation: each vertex, visited in an order drawn from seed, moves to the partition holding the most of
+// its neighbors if that reduces the edge cut and leaves the destination no larger than (1 + imbalance) times the mean partition size.
+// Stops after iterations passes or when a pass moves no vertex.  Partitions are sorted and never empty.
+func LabelPropagationPartitions(G *Core.Network, count int, iterations int, imbalance float64, seed int64) ([][]uint32, error) {
+	return LabelPropagationPartitionsContext(context.Background(), G, count, iterations, imbalance, seed, nil)
+}
+
+// As LabelPropagationPartitions, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, after each pass
+func LabelPropagationPartitionsContext(ctx context.Context, G *Core.Network, count int, iterations int, imbalance float64, seed int64, progress ProgressFunc) ([][]uint32, error) {
+	if imbalance < 0 {
+		return nil, Core.NewNetworkArgumentError("Imbalance must be non-negative")
+	}
+	initial, err := BFSPartitions(G, count)
+	if err != nil {
+		return nil, err
+	}
+	if len(initial) < 2 {
+		return initial, nil
+	}
+
+	vertices := G.Vertices(true)
+	partitionOf := make(map[uint32]int, len(vertices))
+	sizes := make([]int, len(initial))
+	for p, partition := range initial {
+		for _, v := range partition {
+			partitionOf[v] = p
+		}
+		sizes[p] = len(partition)
+	}
+	// the tolerance keeps rounding error from admitting one vertex more than the bound, e.g., 50 * 1.1
+	maxSize := int(math.Ceil(float64(len(vertices))/float64(len(initial))*(1+imbalance) - 1e-9))
+
+	neighbors := make(map[uint32][]traversalEdge, len(vertices))
+	for _, v := range vertices {
+		neighbors[v] = traversalNeighbors(G, v, AnyDirection)
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	reporter := newProgressReporter("LabelPropagationPartitionsContext", progress)
+	for i := 0; i < iterations; i++ {
+		moved := 0
+		for _, idx := range r.Perm(len(vertices)) {
+			if cancelled(ctx) {
+				return nil, ctx.Err()
+			}
+			v := vertices[idx]
+			from := partitionOf[v]
+			if sizes[from] == 1 {
+				continue
+			}
+			counts := make(map[int]int)
+			for _, n := range neighbors[v] {
+				counts[partitionOf[n.to]]++
+			}
+			// the lowest partition wins ties so the result depends only on the seed
+			best := from
+			for p := range sizes {
+				if counts[p] > counts[best] && sizes[p] < maxSize {
+					best = p
+				}
+			}
+			if best != from {
+				partitionOf[v] = best
+				sizes[from]--
+				sizes[best]++
+				moved++
+			}
+		}
+		reporter.emit(i+1, -1)
+		if moved == 0 {
+			break
+		}
+	}
+
+	retVal := make([][]uint32, len(initial))
+	for _, v := range vertices {
+		retVal[partitionOf[v]] = append(retVal[partitionOf[v]], v)
+	}
+	return retVal, nil
+}
+
+// Number of edges whose endpoints lie in different partitions
+func EdgeCut(G *Core.Network, partitions [][]uint32) (int, error) {
+	if err := ValidatePartitions(G, partitions); err != nil {
+		return 0, err
+	}
+	partitionOf := partitionIndex(partitions)
+	retVal := 0
+	for _, u := range G.Vertices(false) {
+		for v := range G.GetNeighbors(u) {
+			if (G.Directed() || u < v) && partitionOf[u] != partitionOf[v] {
+				retVal++
+			}
+		}
+	}
+	return retVal, nil
+}
+
+// Returns an error unless every vertex of G lies in exactly one partition and no partition is empty
+func ValidatePartitions(G *Core.Network, partitions [][]uint32) error {
+	if G == nil {
+		return Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	seen := make(map[uint32]bool, G.Order())
+	for p, partition := range partitions {
+		if len(partition) == 0 {
+			return Core.NewNetworkArgumentError(Sprintf("Partition %d is empty", p))
+		}
+		for _, v := range partition {
+			if !G.HasVertex(v) {
+				return Core.NewNetworkArgumentError(Sprintf("Vertex %d of partition %d is not in the network", v, p))
+			}
+			if seen[v] {
+				return Core.NewNetworkArgumentError(Sprintf("Vertex %d appears in more than one partition", v))
+			}
+			seen[v] = true
+		}
+	}
+	if len(seen) != G.Order() {
+		return Core.NewNetworkArgumentError(Sprintf("Partitions hold %d of the network's %d vertices", len(seen), G.Order()))
+	}
+	return nil
+}
+
+func partitionIndex(partitions [][]uint32) map[uint32]int {
+	retVal := make(map[uint32]int)
+	for p, partition := range partitions {
+		for _, v := range partition {
+			retVal[v] = p
+		}
+	}
+	return retVal
+}
+
+func sortedCopy(vertices []uint32) []uint32 {
+	retVal := make([]uint32, len(vertices))
+	copy(retVal, vertices)
+	sort.Slice(retVal, func(i, j int) bool { return retVal[i] < retVal[j] })
+	return retVal
+}
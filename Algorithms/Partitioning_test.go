@@ -0,0 +1,139 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// a ring of n vertices whose ids are shuffled, so that contiguous ranges of ids are scattered around the ring
+func makeShuffledRing(n int, r *rand.Rand) *Core.Network {
+	G := Core.NewNetwork(false)
+	ids := r.Perm(n)
+	for i := 0; i < n; i++ {
+		_ = G.AddEdge(uint32(ids[i]), uint32(ids[(i+1)%n]), 1.0)
+	}
+	return G
+}
+
+func TestBFSPartitions(t *testing.T) {
+	G := makeShuffledRing(200, rand.New(rand.NewSource(1)))
+	partitions, err := BFSPartitions(G, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ValidatePartitions(G, partitions); err != nil {
+		t.Fatal(err)
+	}
+	for _, partition := range partitions {
+		if len(partition) != 50 {
+			t.Errorf("Expected partitions of 50 vertices, found %d", len(partition))
+		}
+	}
+
+	cut, _ := EdgeCut(G, partitions)
+	contiguous, _ := ContiguousPartitions(G.Vertices(true), 4)
+	contiguousCut, _ := EdgeCut(G, contiguous)
+	// breadth-first search from a vertex grows in both directions around the ring, so its pieces are arcs, save the first
+	if cut > 8 || cut >= contiguousCut {
+		t.Errorf("Expected a small cut from BFS growth, found %d against %d for contiguous ranges", cut, contiguousCut)
+	}
+
+	if partitions, _ = BFSPartitions(G, 500); len(partitions) != 200 {
+		t.Errorf("Expected the partition count to be limited by the order, found %d partitions", len(partitions))
+	}
+}
+
+func TestLabelPropagationPartitions(t *testing.T) {
+	G, _ := Generators.ErdosRenyiGnp(300, 0.02, false, rand.New(rand.NewSource(2)))
+	initial, _ := BFSPartitions(G, 6)
+	initialCut, _ := EdgeCut(G, initial)
+
+	partitions, err := LabelPropagationPartitions(G, 6, 20, 0.1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ValidatePartitions(G, partitions); err != nil {
+		t.Fatal(err)
+	}
+	cut, _ := EdgeCut(G, partitions)
+	if cut > initialCut {
+		t.Errorf("Label propagation increased the cut from %d to %d", initialCut, cut)
+	}
+	for _, partition := range partitions {
+		if len(partition) > 55 {
+			t.Errorf("Partition of %d vertices exceeds the imbalance bound of 55", len(partition))
+		}
+	}
+
+	again, _ := LabelPropagationPartitions(G, 6, 20, 0.1, 7)
+	if !reflect.DeepEqual(partitions, again) {
+		t.Error("Expected the same partitions from the same seed")
+	}
+}
+
+func TestValidatePartitions(t *testing.T) {
+	G := makeTwoCliques()
+	if err := ValidatePartitions(G, [][]uint32{{0, 1, 2, 3, 4, 5}, {10, 11, 12, 13, 14}}); err == nil {
+		t.Error("Expected an error when a vertex is missing")
+	}
+	if err := ValidatePartitions(G, [][]uint32{{0, 1, 2, 3, 4, 5, 10}, {10, 11, 12, 13, 14, 15}}); err == nil {
+		t.Error("Expected an error when a vertex is repeated")
+	}
+	if err := ValidatePartitions(G, [][]uint32{{0, 1, 2, 3, 4, 5, 10, 11, 12, 13, 14, 15}, {}}); err == nil {
+		t.Error("Expected an error for an empty partition")
+	}
+	cut, err := EdgeCut(G, [][]uint32{{0, 1, 2, 3, 4, 5}, {10, 11, 12, 13, 14, 15}})
+	if err != nil || cut != 1 {
+		t.Errorf("Expected a cut of 1 between the cliques, found %d", cut)
+	}
+}
+
+func TestSLPAWithPartitions(t *testing.T) {
+	G := makeTwoCliques()
+	partitions, _ := BFSPartitions(G, 2)
+
+	options := NewSLPAOptions()
+	options.Deterministic = true
+	expected, _ := SLPAWithOptions(G, options)
+	options.Partitions = partitions
+	communities, err := SLPAWithOptions(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(communities, expected) {
+		t.Error("Expected the synchronous communities not to depend on the partitions")
+	}
+
+	options.Deterministic = false
+	if _, err = SLPAWithOptions(G, options); err != nil {
+		t.Fatal(err)
+	}
+	options.Partitions = [][]uint32{{0, 1}}
+	if _, err = SLPAWithOptions(G, options); err == nil {
+		t.Error("Expected an error for partitions missing vertices")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Progress reporting for long-running algorithms.  The Context variants of the algorithms accept an optional ProgressFunc and stop,
+// returning the context's error, when the context is cancelled or its deadline passes.
+
+package Algorithms
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Progress struct {
+	Algorithm string        // name of the Context function the caller invoked
+	Iteration int           // iterations, rounds, phases, or components completed, depending on the algorithm
+	Partition int           // partition (goroutine) reporting, or -1 when the report covers the whole network
+	Elapsed   time.Duration // time since the algorithm started
+}
+
+// Called as an algorithm makes progress.  Calls are serialized, but may come from any goroutine, so the function should return quickly.
+type ProgressFunc func(Progress)
+
+// Returns a ProgressFunc that sends each report on channel, dropping reports the receiver is not ready for rather than slowing the algorithm
+func ProgressChannel(channel chan<- Progress) ProgressFunc {
+	return func(p Progress) {
+		select {
+		case channel <- p:
+		default:
+		}
+	}
+}
+
+type progressReporter struct {
+	lock      sync.Mutex
+	algorithm string
+	start     time.Time
+	report    ProgressFunc
+}
+
+func newProgressReporter(algorithm string, report ProgressFunc) *progressReporter {
+	return &progressReporter{algorithm: algorithm, start: time.Now(), report: report}
+}
+
+func (reporter *progressReporter) emit(iteration int, partition int) {
+	if reporter == nil || reporter.report == nil {
+		return
+	}
+	reporter.lock.Lock()
+	defer reporter.lock.Unlock()
+	reporter.report(Progress{Algorithm: reporter.algorithm, Iteration: iteration, Partition: partition, Elapsed: time.Since(reporter.start)})
+}
+
+// true once ctx has been cancelled or has timed out
+func cancelled(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
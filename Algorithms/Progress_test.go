@@ -0,0 +1,318 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Generators"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSLPAProgress(t *testing.T) {
+	G := makeTwoCliques()
+	for _, deterministic := range []bool{true, false} {
+		options := NewSLPAOptions()
+		options.Deterministic = deterministic
+		options.Iterations = 10
+		options.ConcurrentCount = 3
+		reports := make(map[int]int)
+		last := 0
+		_, err := SLPAWithOptionsContext(context.Background(), G, options, func(p Progress) {
+			reports[p.Partition]++
+			if p.Partition == -1 && p.Iteration != last+1 {
+				t.Errorf("Expected iteration %d to be reported next, found %d", last+1, p.Iteration)
+			}
+			last = p.Iteration
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deterministic {
+			if len(reports) != 1 || reports[-1] != options.Iterations {
+				t.Errorf("Expected one report per iteration for the whole network, found %v", reports)
+			}
+		} else {
+			for partition := 0; partition < options.ConcurrentCount; partition++ {
+				if reports[partition] != options.Iterations {
+					t.Errorf("Expected %d reports from partition %d, found %d", options.Iterations, partition, reports[partition])
+				}
+			}
+		}
+	}
+}
+
+func TestSLPACancel(t *testing.T) {
+	G, _ := Generators.ErdosRenyiGnp(400, 0.03, false, rand.New(rand.NewSource(3)))
+	for _, deterministic := range []bool{true, false} {
+		options := NewSLPAOptions()
+		options.Deterministic = deterministic
+		options.Iterations = 100000
+		options.ConcurrentCount = 4
+		ctx, cancel := context.WithCancel(context.Background())
+		// cancel from within the run once it is under way
+		_, err := SLPAWithOptionsContext(ctx, G, options, func(p Progress) {
+			if p.Iteration >= 2 {
+				cancel()
+			}
+		})
+		cancel()
+		if err != context.Canceled {
+			t.Errorf("Expected the run to be cancelled (deterministic %v), found %v", deterministic, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ConcurrentSLPAContext(ctx, G, 20, 0.3, 1, 4, 2, nil); err != context.Canceled {
+		t.Errorf("Expected a cancelled context to stop ConcurrentSLPAContext, found %v", err)
+	}
+	if _, err := SLPADetailedContext(ctx, G, NewSLPAOptions(), nil); err != context.Canceled {
+		t.Errorf("Expected a cancelled context to stop SLPADetailedContext, found %v", err)
+	}
+
+	// invalid arguments are errors rather than panics
+	if _, err := ConcurrentSLPAContext(context.Background(), nil, 5, 0.3, 1, 1, 1, nil); err == nil {
+		t.Error("Expected an error for a nil network")
+	}
+	if _, err := ConcurrentSLPAContext(context.Background(), G, 5, 0.3, 1, 0, 1, nil); err == nil {
+		t.Error("Expected an error for zero goroutines")
+	}
+	if _, err := ConcurrentSLPAContext(context.Background(), G, 0, 0.3, 1, 4, 1, nil); err == nil {
+		t.Error("Expected an error for zero iterations")
+	}
+}
+
+func TestContextVariantsCancel(t *testing.T) {
+	G, _ := Generators.ErdosRenyiGnp(100, 0.1, false, rand.New(rand.NewSource(5)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := ConcurrentJonesPlassmannContext(ctx, G, 3, 1, nil); err != context.Canceled {
+		t.Errorf("ConcurrentJonesPlassmannContext: expected cancellation, found %v", err)
+	}
+	if _, err := ConcurrentTriangleCountContext(ctx, G, 3, nil); err != context.Canceled {
+		t.Errorf("ConcurrentTriangleCountContext: expected cancellation, found %v", err)
+	}
+	if _, err := AverageClusteringContext(ctx, G, Unweighted, 3, nil); err != context.Canceled {
+		t.Errorf("AverageClusteringContext: expected cancellation, found %v", err)
+	}
+	if _, err := TransitivityContext(ctx, G, 3, nil); err != context.Canceled {
+		t.Errorf("TransitivityContext: expected cancellation, found %v", err)
+	}
+	if _, err := MaxFlowContext(ctx, G, 0, 99, nil); err != context.Canceled {
+		t.Errorf("MaxFlowContext: expected cancellation, found %v", err)
+	}
+	if _, _, _, err := StoerWagnerMinCutContext(ctx, G, nil); err != context.Canceled {
+		t.Errorf("StoerWagnerMinCutContext: expected cancellation, found %v", err)
+	}
+	H, R, B := randomBipartite(20, 20, 0.3, rand.New(rand.NewSource(7)))
+	if _, err := HungarianMatchingContext(ctx, H, R, B, nil); err != context.Canceled {
+		t.Errorf("HungarianMatchingContext: expected cancellation, found %v", err)
+	}
+	if _, err := HopcroftKarpContext(ctx, H, R, B, nil); err != context.Canceled {
+		t.Errorf("HopcroftKarpContext: expected cancellation, found %v", err)
+	}
+	if _, err := BipartiteProjectionContext(ctx, H, R, B, NewmanWeighting, nil); err != context.Canceled {
+		t.Errorf("BipartiteProjectionContext: expected cancellation, found %v", err)
+	}
+	if _, err := BlossomMatchingContext(ctx, G, nil); err != context.Canceled {
+		t.Errorf("BlossomMatchingContext: expected cancellation, found %v", err)
+	}
+	D, _ := Generators.ErdosRenyiGnp(100, 0.1, true, rand.New(rand.NewSource(6)))
+	if _, err := ChuLiuEdmondsArborescenceContext(ctx, D, 0, false, nil); err != context.Canceled {
+		t.Errorf("ChuLiuEdmondsArborescenceContext: expected cancellation, found %v", err)
+	}
+	if _, err := CoreNumbersContext(ctx, G, TotalDegree, nil); err != context.Canceled {
+		t.Errorf("CoreNumbersContext: expected cancellation, found %v", err)
+	}
+	if _, err := TrussNumbersContext(ctx, G, nil); err != context.Canceled {
+		t.Errorf("TrussNumbersContext: expected cancellation, found %v", err)
+	}
+	if _, err := KTrussContext(ctx, G, 3, nil); err != context.Canceled {
+		t.Errorf("KTrussContext: expected cancellation, found %v", err)
+	}
+	if _, err := BiconnectedComponentsContext(ctx, G, nil); err != context.Canceled {
+		t.Errorf("BiconnectedComponentsContext: expected cancellation, found %v", err)
+	}
+	if _, err := LocalClusteringContext(ctx, G, 0, Onnela, nil); err != context.Canceled {
+		t.Errorf("LocalClusteringContext: expected cancellation, found %v", err)
+	}
+	if _, err := LabelPropagationPartitionsContext(ctx, G, 4, 10, 0.1, 1, nil); err != context.Canceled {
+		t.Errorf("LabelPropagationPartitionsContext: expected cancellation, found %v", err)
+	}
+}
+
+func TestContextVariantsProgress(t *testing.T) {
+	G, _ := Generators.ErdosRenyiGnp(100, 0.1, false, rand.New(rand.NewSource(5)))
+	count := 0
+	progress := func(p Progress) { count++ }
+
+	colors, err := ConcurrentJonesPlassmannContext(context.Background(), G, 3, 1, progress)
+	if err != nil || ValidateColoring(G, colors) != nil || count == 0 {
+		t.Errorf("Expected a valid coloring with progress reported, found error %v after %d reports", err, count)
+	}
+
+	count = 0
+	triangles, err := ConcurrentTriangleCountContext(context.Background(), G, 4, progress)
+	if err != nil || triangles != ConcurrentTriangleCount(G, 1) || count != 4 {
+		t.Errorf("Expected the triangle count with one report per partition, found %d triangles after %d reports", triangles, count)
+	}
+
+	count = 0
+	weight, _, _, err := StoerWagnerMinCutContext(context.Background(), G, progress)
+	expected, _, _, _ := StoerWagnerMinCut(G)
+	if err != nil || weight != expected || count != G.Order()-1 {
+		t.Errorf("Expected cut weight %f after %d phases, found %f after %d reports", expected, G.Order()-1, weight, count)
+	}
+
+	// the sequential Context variants agree with the functions they extend and report at least once
+	H, R, B := randomBipartite(20, 20, 0.3, rand.New(rand.NewSource(7)))
+	D, _ := Generators.ErdosRenyiGnp(60, 0.2, true, rand.New(rand.NewSource(8)))
+	checks := map[string]func() (interface{}, interface{}, error){
+		"HopcroftKarpContext": func() (interface{}, interface{}, error) {
+			m, err := HopcroftKarpContext(context.Background(), H, R, B, progress)
+			expected, _ := HopcroftKarp(H, R, B)
+			return m, expected, err
+		},
+		"BipartiteProjectionContext": func() (interface{}, interface{}, error) {
+			projection, err := BipartiteProjectionContext(context.Background(), H, R, B, NewmanWeighting, progress)
+			expected, _ := BipartiteProjection(H, R, B, NewmanWeighting)
+			return projection, expected, err
+		},
+		"BlossomMatchingContext": func() (interface{}, interface{}, error) {
+			m, err := BlossomMatchingContext(context.Background(), G, progress)
+			expected, _ := BlossomMatching(G)
+			return m, expected, err
+		},
+		"ChuLiuEdmondsArborescenceContext": func() (interface{}, interface{}, error) {
+			a, err := ChuLiuEdmondsArborescenceContext(context.Background(), D, 0, false, progress)
+			expected, _ := ChuLiuEdmondsArborescence(D, 0, false)
+			return a.Size(), expected.Size(), err
+		},
+		"CoreNumbersContext": func() (interface{}, interface{}, error) {
+			cores, err := CoreNumbersContext(context.Background(), G, TotalDegree, progress)
+			expected, _ := CoreNumbers(G, TotalDegree)
+			return cores, expected, err
+		},
+		"TrussNumbersContext": func() (interface{}, interface{}, error) {
+			truss, err := TrussNumbersContext(context.Background(), G, progress)
+			expected, _ := TrussNumbers(G)
+			return truss, expected, err
+		},
+		"BiconnectedComponentsContext": func() (interface{}, interface{}, error) {
+			tree, err := BiconnectedComponentsContext(context.Background(), G, progress)
+			expected, _ := BiconnectedComponents(G)
+			return tree, expected, err
+		},
+		"LocalClusteringContext": func() (interface{}, interface{}, error) {
+			c, err := LocalClusteringContext(context.Background(), G, 0, Onnela, progress)
+			return c, LocalClustering(G, 0, Onnela), err
+		},
+		"LabelPropagationPartitionsContext": func() (interface{}, interface{}, error) {
+			partitions, err := LabelPropagationPartitionsContext(context.Background(), G, 4, 10, 0.1, 1, progress)
+			expected, _ := LabelPropagationPartitions(G, 4, 10, 0.1, 1)
+			return partitions, expected, err
+		},
+	}
+	for name, check := range checks {
+		count = 0
+		found, expected, err := check()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(found, expected) {
+			t.Errorf("%s: expected the result of the function it extends", name)
+		}
+		if count == 0 {
+			t.Errorf("%s: expected progress to be reported", name)
+		}
+	}
+}
+
+func TestProgressAlgorithmNames(t *testing.T) {
+	G := makeTwoCliques()
+	ctx := context.Background()
+	calls := map[string]func(ProgressFunc) error{
+		"ConcurrentSLPAContext": func(progress ProgressFunc) error {
+			_, err := ConcurrentSLPAContext(ctx, G, 10, 0.1, 1, 2, 2, progress)
+			return err
+		},
+		"SLPAWithOptionsContext": func(progress ProgressFunc) error {
+			_, err := SLPAWithOptionsContext(ctx, G, NewSLPAOptions(), progress)
+			return err
+		},
+		"SLPADetailedContext": func(progress ProgressFunc) error {
+			_, err := SLPADetailedContext(ctx, G, NewSLPAOptions(), progress)
+			return err
+		},
+		"LouvainContext": func(progress ProgressFunc) error {
+			_, err := LouvainContext(ctx, G, NewLouvainOptions(), progress)
+			return err
+		},
+		"LouvainDendrogramContext": func(progress ProgressFunc) error {
+			_, err := LouvainDendrogramContext(ctx, G, NewLouvainOptions(), progress)
+			return err
+		},
+		"LeidenContext": func(progress ProgressFunc) error {
+			_, err := LeidenContext(ctx, G, NewLeidenOptions(), progress)
+			return err
+		},
+		"LeidenDendrogramContext": func(progress ProgressFunc) error {
+			_, err := LeidenDendrogramContext(ctx, G, NewLeidenOptions(), progress)
+			return err
+		},
+		"InfomapContext": func(progress ProgressFunc) error {
+			_, err := InfomapContext(ctx, G, NewInfomapOptions(), progress)
+			return err
+		},
+		"LabelPropagationContext": func(progress ProgressFunc) error {
+			_, err := LabelPropagationContext(ctx, G, NewLPAOptions(), progress)
+			return err
+		},
+	}
+	for name, call := range calls {
+		names := make(map[string]int)
+		if err := call(func(p Progress) { names[p.Algorithm]++ }); err != nil {
+			t.Fatal(err)
+		}
+		if len(names) != 1 || names[name] == 0 {
+			t.Errorf("Expected every report to name %s, found %v", name, names)
+		}
+	}
+}
+
+func TestProgressChannel(t *testing.T) {
+	channel := make(chan Progress, 1)
+	report := ProgressChannel(channel)
+	report(Progress{Iteration: 1})
+	report(Progress{Iteration: 2})
+	if p := <-channel; p.Iteration != 1 {
+		t.Errorf("Expected the first report to be delivered, found iteration %d", p.Iteration)
+	}
+	select {
+	case p := <-channel:
+		t.Errorf("Expected the second report to be dropped, found iteration %d", p.Iteration)
+	default:
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// One-mode projection of a bipartite network onto either vertex set
+// Two vertices are joined in the projection when they share a neighbor in the other set.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+)
+
+// how the weight of an edge in a projection is computed from the shared neighbors of its endpoints
+type ProjectionWeighting int
+
+const (
+	CountWeighting      ProjectionWeighting = iota // the number of shared neighbors
+	NewmanWeighting                                // each shared neighbor k contributes 1 / (deg(k) - 1), after Newman, Phys. Rev. E 64, 016132, 2001
+	JaccardWeighting                               // shared neighbors divided by the union of the neighborhoods
+	HyperbolicWeighting                            // each shared neighbor k contributes 1 / deg(k)
+)
+
+// Projects a bipartite network onto the vertex set onto, whose edges all lead to vertices in other; R and B from ConcurrentBipartite
+// may be passed in either order.  The projection is a new undirected network holding every vertex of onto.  Edge direction is ignored.
+func BipartiteProjection(G *Core.Network, onto []uint32, other []uint32, weighting ProjectionWeighting) (*Core.Network, error) {
+	return BipartiteProjectionContext(context.Background(), G, onto, other, weighting, nil)
+}
+
+// As BipartiteProjection, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as the pairs
+// sharing each vertex of other are accumulated
+func BipartiteProjectionContext(ctx context.Context, G *Core.Network, onto []uint32, other []uint32, weighting ProjectionWeighting, progress ProgressFunc) (*Core.Network, error) {
+	if _, err := bipartiteAdjacency(G, onto, other); err != nil {
+		return nil, err
+	}
+
+	reporter := newProgressReporter("BipartiteProjectionContext", progress)
+	// accumulate per pair, keyed by the lower then the higher vertex id
+	weights := make(map[uint32]map[uint32]float64)
+	for n, k := range other {
+		if cancelled(ctx) {
+			return nil, ctx.Err()
+		}
+		neighbors := traversalNeighbors(G, k, AnyDirection)
+		degree := float64(len(neighbors))
+		var contribution float64
+		switch weighting {
+		case NewmanWeighting:
+			contribution = 1.0 / (degree - 1.0)
+		case HyperbolicWeighting:
+			contribution = 1.0 / degree
+		default:
+			contribution = 1.0
+		}
+		// neighbors are sorted, so u < v throughout
+		for i, u := range neighbors {
+			for _, v := range neighbors[i+1:] {
+				if _, ok := weights[u.to]; !ok {
+					weights[u.to] = make(map[uint32]float64)
+				}
+				weights[u.to][v.to] += contribution
+			}
+		}
+		reporter.emit(n+1, -1)
+	}
+
+	retVal := Core.NewNetwork(false)
+	degree := make(map[uint32]int, len(onto))
+	for _, v := range onto {
+		retVal.AddVertex(v)
+		degree[v] = len(traversalNeighbors(G, v, AnyDirection))
+	}
+	for u, targets := range weights {
+		for v, wt := range targets {
+			if weighting == JaccardWeighting {
+				wt = wt / (float64(degree[u]+degree[v]) - wt)
+			}
+			_ = retVal.AddEdge(u, v, float32(wt))
+		}
+	}
+	return retVal, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"testing"
+)
+
+func TestBipartiteProjection(t *testing.T) {
+	// users 1-3 interact with items 10-12: 1 and 2 share 10 and 11, 2 and 3 share 12
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(1, 10, 1.0)
+	_ = G.AddEdge(1, 11, 1.0)
+	_ = G.AddEdge(2, 10, 1.0)
+	_ = G.AddEdge(2, 11, 1.0)
+	_ = G.AddEdge(2, 12, 1.0)
+	_ = G.AddEdge(3, 12, 1.0)
+	_ = G.AddEdge(4, 12, 1.0)
+	G.AddVertex(5)
+	users := []uint32{1, 2, 3, 4, 5}
+	items := []uint32{10, 11, 12}
+
+	P, err := BipartiteProjection(G, users, items, CountWeighting)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if P.Directed() || P.Order() != 5 || P.Size() != 4 {
+		t.Fatalf("Expected an undirected projection of order 5 and size 4, found %d and %d", P.Order(), P.Size())
+	}
+	if P.EdgeWeight(1, 2) != 2 || P.EdgeWeight(3, 2) != 1 {
+		t.Errorf("Expected counts 2 and 1, found %f and %f", P.EdgeWeight(1, 2), P.EdgeWeight(3, 2))
+	}
+
+	P, _ = BipartiteProjection(G, users, items, NewmanWeighting)
+	if math.Abs(float64(P.EdgeWeight(1, 2))-2.0) > 1e-6 || math.Abs(float64(P.EdgeWeight(2, 3))-0.5) > 1e-6 {
+		t.Errorf("Expected Newman weights 2 and 0.5, found %f and %f", P.EdgeWeight(1, 2), P.EdgeWeight(2, 3))
+	}
+	P, _ = BipartiteProjection(G, users, items, HyperbolicWeighting)
+	if math.Abs(float64(P.EdgeWeight(1, 2))-1.0) > 1e-6 || math.Abs(float64(P.EdgeWeight(3, 4))-1.0/3.0) > 1e-6 {
+		t.Errorf("Expected hyperbolic weights 1 and 1/3, found %f and %f", P.EdgeWeight(1, 2), P.EdgeWeight(3, 4))
+	}
+	P, _ = BipartiteProjection(G, users, items, JaccardWeighting)
+	if math.Abs(float64(P.EdgeWeight(1, 2))-2.0/3.0) > 1e-6 || math.Abs(float64(P.EdgeWeight(3, 4))-1.0) > 1e-6 {
+		t.Errorf("Expected Jaccard weights 2/3 and 1, found %f and %f", P.EdgeWeight(1, 2), P.EdgeWeight(3, 4))
+	}
+
+	P, _ = BipartiteProjection(G, items, users, CountWeighting)
+	if P.Order() != 3 || P.EdgeWeight(10, 11) != 2 || P.EdgeWeight(10, 12) != 1 {
+		t.Errorf("Unexpected projection onto the items")
+	}
+
+	_ = G.AddEdge(1, 2, 1.0)
+	if _, err = BipartiteProjection(G, users, items, CountWeighting); err == nil {
+		t.Errorf("Expected an error for an edge within the projected set")
+	}
+}
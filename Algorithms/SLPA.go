@@ -30,10 +30,10 @@
 package Algorithms
 
 import (
+	"context"
 	"github.com/smohr1824/Networks/Core"
 	"math/rand"
 	"sync"
-	"time"
 )
 
 // message used by a goroutine to communicate which iteration it is on to the main calling routine, or for
@@ -60,6 +60,39 @@ type LabelObservation struct {
 
 // Concurrent implementation of SLPA community detection algorithm per Kuzman, Chen, Szymanski 2015
 func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int64, concurrentCount int, minCommunitySize int) map[int][]uint32 {
+	retVal, _ := ConcurrentSLPAContext(context.Background(), G, iterations, threshold, seed, concurrentCount, minCommunitySize, nil)
+	return retVal
+}
+
+// As ConcurrentSLPA, but stops every goroutine and returns the context's error if ctx is cancelled or times out.  Each partition
+// reports progress, if progress is not nil, as it completes an iteration.  A nil network, fewer than one iteration, or fewer than
+// one goroutine is an error.
+func ConcurrentSLPAContext(ctx context.Context, G *Core.Network, iterations int, threshold float64, seed int64, concurrentCount int, minCommunitySize int, progress ProgressFunc) (map[int][]uint32, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if iterations < 1 {
+		return nil, Core.NewNetworkArgumentError("SLPA requires at least one iteration")
+	}
+	if concurrentCount < 1 {
+		return nil, Core.NewNetworkArgumentError("ConcurrentCount must be at least 1")
+	}
+	if G.Order() == 0 {
+		return make(map[int][]uint32), nil
+	}
+	if concurrentCount > G.Order() {
+		concurrentCount = G.Order()
+	}
+	partitions, _ := ContiguousPartitions(G.Vertices(true), concurrentCount)
+	nodeLabelMemory, err := concurrentSLPA(ctx, G, iterations, seed, partitions, defaultSLPARules(), nil, newProgressReporter("ConcurrentSLPAContext", progress))
+	if err != nil {
+		return nil, err
+	}
+	return PostProcess(nodeLabelMemory, threshold, minCommunitySize), nil
+}
+
+// runs the propagation with one goroutine per partition and returns the label memory of every vertex; stats, if not nil, collects counts for each iteration
+func concurrentSLPA(ctx context.Context, G *Core.Network, iterations int, seed int64, partitionSlices [][]uint32, rules *slpaRules, stats *slpaStats, reporter *progressReporter) (*sync.Map, error) {
 	vertices := G.Vertices(true)
 	order := G.Order()
 
@@ -71,40 +104,50 @@ func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int
 	}
 
 
-	// can't have more partitions than nodes; every partition must have at least one node
-	if concurrentCount > order {
-		concurrentCount = order
-	}
-	partSize := order/concurrentCount	// very important: integer division -- last partition may have up to partSize + (partSize - 1) nodes
-	partCount := concurrentCount
+	// every partition has at least one node
+	concurrentCount := len(partitionSlices)
 
-	partition := 0	// one-based index of partitions
+	// building the dependencies here is essential to the control structure synchronizing the goroutines,
+	// and creating the lists of nodes with neighbors outside the partition and nodes with neighbors inside the partition is a natural side effect.
+	// Unfortunately, it sacrifices some concurrency
+	dependsOnList, dependencyToList, internals, externals := partitionDependencies(G, partitionSlices, rules.speakers)
 
-	// construct the partitions for each goroutine running concurrently
-	partitionSlices := make([][]uint32, partCount)
+	canIGoChannel := make(chan IterationMessage, concurrentCount)	// multiplexed channel for goroutines to ask if they can proceed
+	goChannels := make([]chan bool, concurrentCount)				// one channel per goroutine to signal proceed with processing, dependencies complete
 
-	// because Go doesn't have a fast way to search a slice of strings, make a parallel array of starting (low) indices of each partition within the array of vertices
-	partitionLows := make([]int, partCount)
+	// Allocate a global map of node names to maps of labels observed
+	// Each observation is the node index and the number of times that label was observed.
+	// Yes, this flies in the face of the Go pattern of passing copies rather than working on one structure.
+	// However, partitions will need to access labels from nodes outside their partition, and the concurrency scheme
+	// ensures out of partition access is read-only AND synchronized such that dependency labels are correct before they are accessed.
+	nodeLabelMemory := new(sync.Map)
+	InitLabels(&vertices, 0, order - 1, nodeLabelMemory)
 
-	for i := 0; i < partCount; i++ {
-		if partition < partCount - 1 {
-			// new partition, populate the slice for the old partition
-			low := i * partSize
-			partitionLows[partition] = low
-			high := low + partSize
-			partitionSlices[partition] = vertices[low:high]
-			partition++
-		} else {
-				low := i * partSize
-				partitionLows[partition] = low
-				high := order
-				partitionSlices[partition] = vertices[low:high]
-		}
+	// done is closed on cancellation so that goroutines waiting on the control loop, or in the middle of an iteration, return
+	done := make(chan struct{})
+	var routines sync.WaitGroup
+	for i:= 0; i < concurrentCount; i++ {
+		goChannels[i] = make(chan bool, 1)
+
+		routines.Add(1)
+		go func(i int) {
+			defer routines.Done()
+			partitionSLPA(i, G, &vertices, &vertIdx, &externals[i], &internals[i], seed, iterations, nodeLabelMemory, canIGoChannel, goChannels[i], rules, stats, done, reporter)
+		}(i)
 	}
 
-	// building the dependencies here is essential to the control structure synchronizing the goroutines,
-	// and creating the lists of nodes with neighbors outside the partition and nodes with neighbors inside the partition is a natural side effect.
-	// Unfortunately, it sacrifices some concurrency
+	if err := controlPartitions(ctx, dependsOnList, dependencyToList, canIGoChannel, goChannels, done, &routines, nil); err != nil {
+		return nil, err
+	}
+	return nodeLabelMemory, nil
+}
+
+// for each partition, the partitions it depends on, the partitions depending on it, its vertices whose neighbors (as given by neighbors) all lie within it,
+// and its vertices with a neighbor outside it
+func partitionDependencies(G *Core.Network, partitionSlices [][]uint32, neighbors func(*Core.Network, uint32) []traversalEdge) ([][]int, [][]int, [][]uint32, [][]uint32) {
+	// partitionOf gives the partition holding each vertex
+	concurrentCount := len(partitionSlices)
+	partitionOf := partitionIndex(partitionSlices)
 	dependsOnList:= make([][]int, concurrentCount)
 	dependencyToList := make([][]int, concurrentCount)
 
@@ -117,20 +160,12 @@ func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int
 	for partitionIdx, partition := range partitionSlices {
 		for _, nodeId := range partition {
 			hasExternalDependencies := false
-			nodeNeighbors := G.GetNeighbors(nodeId)
-			for neighbor := range nodeNeighbors {
-				indexInVertices := vertIdx[neighbor]
-				low := partitionLows[partitionIdx]
-				if indexInVertices < low || indexInVertices >= low + partSize {
+			nodeNeighbors := neighbors(G, nodeId)
+			for _, speaker := range nodeNeighbors {
+				foundIn := partitionOf[speaker.to]
+				if foundIn != partitionIdx {
 					// has neighbors external to the partition
 					hasExternalDependencies = true
-					// not in the partition, use integer division to get the partition number where it is found (dependency on the partitioning schema!)
-					// also assume that the network is consistent and neighbor really is in some partition of the network
-
-					foundIn := indexInVertices/partSize
-					if foundIn >= concurrentCount {
-						foundIn = concurrentCount - 1
-					}
 					if !intInSlice(foundIn, dependsOnList[partitionIdx]) {
 						dependsOnList[partitionIdx] = append(dependsOnList[partitionIdx], foundIn)
 					}
@@ -148,27 +183,18 @@ func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int
 			}
 		}
 	}
-	currentIteration := make([] int, concurrentCount)
+	return dependsOnList, dependencyToList, internals, externals
+}
 
-	canIGoChannel := make(chan IterationMessage, concurrentCount)	// multiplexed channel for goroutines to ask if they can proceed
+// the control loop: grants each goroutine permission to proceed to its next iteration once the partitions it depends on are no more than one
+// iteration apart, until every goroutine reports it has finished.  stop, if not nil, is consulted with the iteration of every goroutine after
+// each message; once it returns true, goroutines asking to proceed are sent false instead.  On cancellation done is closed and the goroutines,
+// counted by routines, are awaited.
+func controlPartitions(ctx context.Context, dependsOnList [][]int, dependencyToList [][]int, canIGoChannel chan IterationMessage, goChannels []chan bool, done chan struct{}, routines *sync.WaitGroup, stop func([]int) bool) error {
+	concurrentCount := len(goChannels)
+	currentIteration := make([] int, concurrentCount)
 	permissionStatus := make([]bool, concurrentCount)				// true if a goroutine is awaiting permission to proceed to the next iteration
-	goChannels := make([]chan bool, concurrentCount)				// one channel per goroutine to signal proceed with processing, dependencies complete
-
-	// Allocate a global map of node names to maps of labels observed
-	// Each observation is the node index and the number of times that label was observed.
-	// Yes, this flies in the face of the Go pattern of passing copies rather than working on one structure.
-	// However, partitions will need to access labels from nodes outside their partition, and the concurrency scheme
-	// ensures out of partition access is read-only AND synchronized such that dependency labels are correct before they are accessed.
-	nodeLabelMemory := new(sync.Map)
-	InitLabels(&vertices, 0, order - 1, nodeLabelMemory)
-	for i:= 0; i < concurrentCount; i++ {
-		currentIteration[i] = 0
-		permissionStatus[i] = false
-		goChannels[i] = make(chan bool, 1)
-
-		go PartitionSLPA(i, G, &vertices, &vertIdx, &externals[i], &internals[i], seed, iterations, nodeLabelMemory, canIGoChannel, goChannels[i])
-	}
-
+	stopping := false
 
 	activeRoutines := concurrentCount
 	for ; activeRoutines > 0; {
@@ -178,11 +204,24 @@ func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int
 				currentIteration[permissionMsg.RoutineId] = permissionMsg.IterationNumber
 				// if the iteration is -1, the goroutine is finished, so collect the results
 				if currentIteration[permissionMsg.RoutineId] == -1 {
-					//fmt.Println(fmt.Sprintf("Goroutine %d ending", permissionMsg.RoutineId))
 					activeRoutines--
 				}
 				permissionStatus[permissionMsg.RoutineId] = true
 
+				// once stopping, every goroutine waiting or asking is told to finish
+				if !stopping && stop != nil && stop(currentIteration) {
+					stopping = true
+				}
+				if stopping {
+					for partitionIdx := range goChannels {
+						if currentIteration[partitionIdx] != -1 && permissionStatus[partitionIdx] {
+							goChannels[partitionIdx] <- false
+							permissionStatus[partitionIdx] = false
+						}
+					}
+					continue
+				}
+
 				// if the dependencies of this partition are ready, signal ok and change permission status to false (not pending)
 				if !DependenciesNotReady(permissionMsg.IterationNumber, dependsOnList[permissionMsg.RoutineId], currentIteration) || activeRoutines == 1 {
 					goChannels[permissionMsg.RoutineId] <- true
@@ -197,15 +236,17 @@ func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int
 					}
 				}
 
-			default:
-				time.Sleep(2 * time.Millisecond)
+			case <-ctx.Done():
+				close(done)
+				routines.Wait()
+				return ctx.Err()
 		}
 	}
 	close(canIGoChannel)
 	for i:=0; i < concurrentCount; i++ {
 		close(goChannels[i])
 	}
-	return PostProcess(nodeLabelMemory, threshold, minCommunitySize)
+	return nil
 }
 
 
@@ -214,9 +255,15 @@ func ConcurrentSLPA(G *Core.Network, iterations int, threshold float64, seed int
 // Returns a list of nodes and their observed labels
 // Vertices is passed by reference to avoid copying very large arrays
 func PartitionSLPA(routineID int, G *Core.Network, vertices *[]uint32, vIndices *map[uint32]int, externals *[]uint32, internals *[]uint32, seed int64, iterations int, nodeLabels *sync.Map, askChannel chan<- IterationMessage, waitChannel <-chan bool) {
+	partitionSLPA(routineID, G, vertices, vIndices, externals, internals, seed, iterations, nodeLabels, askChannel, waitChannel, defaultSLPARules(), nil, nil, nil)
+}
+
+// done, if not nil, is closed when the run is cancelled
+func partitionSLPA(routineID int, G *Core.Network, vertices *[]uint32, vIndices *map[uint32]int, externals *[]uint32, internals *[]uint32, seed int64, iterations int, nodeLabels *sync.Map, askChannel chan<- IterationMessage, waitChannel <-chan bool, rules *slpaRules, stats *slpaStats, done <-chan struct{}, reporter *progressReporter) {
 	externalIndices := make([]int, len(*externals)) // indices of the external nodes relative to the start of partition
 
-	r := rand.New(rand.NewSource(seed))
+	// each partition draws from its own stream
+	r := rand.New(rand.NewSource(seed + int64(routineID)))
 	for i := 0; i < len(*externals); i++ {
 		externalIndices[i] = (*vIndices)[(*externals)[i]]
 	}
@@ -225,58 +272,84 @@ func PartitionSLPA(routineID int, G *Core.Network, vertices *[]uint32, vIndices
 		internalIndices[i] = (*vIndices)[(*internals)[i]]
 	}
 	for i := 0; i < iterations; i++ {
-		DoOneIteration(vertices, G, &externalIndices, nodeLabels, r)
+		if !doOneIteration(vertices, G, &externalIndices, nodeLabels, r, rules, stats.at(i), done) {
+			return
+		}
 
 		if i < iterations - 1 {
 			permissionSlip := IterationMessage{RoutineId: routineID, IterationNumber: i + 1}
-			askChannel <- permissionSlip
-			<-waitChannel
+			select {
+			case askChannel <- permissionSlip:
+			case <-done:
+				return
+			}
+			select {
+			case <-waitChannel:
+			case <-done:
+				return
+			}
 
 			// proceed with internal dependencies
-			DoOneIteration(vertices, G, &internalIndices, nodeLabels, r)
+			if !doOneIteration(vertices, G, &internalIndices, nodeLabels, r, rules, stats.at(i), done) {
+				return
+			}
+			reporter.emit(i + 1, routineID)
 		} else {
 
 			// proceed with internal dependencies
-			DoOneIteration(vertices, G, &internalIndices, nodeLabels, r)
+			if !doOneIteration(vertices, G, &internalIndices, nodeLabels, r, rules, stats.at(i), done) {
+				return
+			}
+			reporter.emit(i + 1, routineID)
 
 			// wait to send termination as we don't want to drop out of the control loop in main
 			terminationSlip := IterationMessage{RoutineId: routineID, IterationNumber: -1}
-			askChannel <- terminationSlip
+			select {
+			case askChannel <- terminationSlip:
+			case <-done:
+			}
 		}
 	}
 }
 
 func DoOneIteration(nodes *[]uint32, G *Core.Network, indices *[]int, nodeLabels *sync.Map, r *rand.Rand) {
-	// rand.Perm does a pseudo-random permutation of the digits [0..len(indices)], so convert to the actual node index
-	for _, i := range rand.Perm(len(*indices)) {
+	doOneIteration(nodes, G, indices, nodeLabels, r, defaultSLPARules(), nil, nil)
+}
+
+// returns false, leaving the iteration incomplete, if done is closed
+func doOneIteration(nodes *[]uint32, G *Core.Network, indices *[]int, nodeLabels *sync.Map, r *rand.Rand, rules *slpaRules, stats *iterationCounter, done <-chan struct{}) bool {
+	// r.Perm does a pseudo-random permutation of the digits [0..len(indices)], so convert to the actual node index
+	for _, i := range r.Perm(len(*indices)) {
+		select {
+		case <-done:
+			return false
+		default:
+		}
 		nodeID := (*nodes)[(*indices)[i]]
-		labelsSeen := make(map[int] int)
-		neighbors := G.GetNeighbors(nodeID)
+		labelsSeen := make(map[int] float64)
+		speakers := rules.speakers(G, nodeID)
 
-		if len(neighbors) == 0 {
+		if len(speakers) == 0 {
 			continue
 		}
-		for neighbor := range neighbors {
-			labelMap, ok := nodeLabels.Load(neighbor)
+		for _, speaker := range speakers {
+			labelMap, ok := nodeLabels.Load(speaker.to)
 			if ok {
-				m := labelMap.(*sync.Map)
-				dist := NewMultinomialLabels(m, r)
-				label := dist.NextSample()
-				count, ok := labelsSeen[label]
-				if ok {
-					labelsSeen[label] = count + 1
-
-				} else {
-					labelsSeen[label] = 1
-				}
+				labels, counts := memorySnapshot(labelMap.(*sync.Map))
+				label := rules.speaker(labels, counts, r)
+				labelsSeen[label] += rules.vote(speaker.weight)
 			}
 		}
 
-		maxLabel := MaxLabel(labelsSeen, r)
+		maxLabel := rules.listener(labelsSeen, r)
 
 		listenerMap, ok := nodeLabels.Load(nodeID)
 		m := listenerMap.(*sync.Map)
 		if ok {
+			if stats != nil {
+				labels, counts := memorySnapshot(m)
+				stats.record(maxLabel, mostFrequentLabel(labels, counts))
+			}
 			count, ok := m.Load(maxLabel)
 			if ok {
 				m.Store(maxLabel, count.(int)+1)
@@ -286,6 +359,7 @@ func DoOneIteration(nodes *[]uint32, G *Core.Network, indices *[]int, nodeLabels
 			nodeLabels.Store(nodeID, m)
 		}
 	}
+	return true
 }
 
 func InitLabels(nodes *[]uint32, partitionStart int, partitionEnd int, observations *sync.Map) {
@@ -392,6 +466,27 @@ func ApplyThreshold(labelset *sync.Map, threshold float64) {
 
 }
 
+// Splits vertices into count contiguous ranges, one per goroutine, returning the ranges and the index of the first vertex of each
+// (because Go doesn't have a fast way to search a slice, the starting indices make it cheap to find which range holds a vertex).
+// Every range has len(vertices)/count vertices except the last, which also takes the remainder.
+func ContiguousPartitions(vertices []uint32, count int) ([][]uint32, []int) {
+	order := len(vertices)
+	partSize := order/count		// very important: integer division
+	partitionSlices := make([][]uint32, count)
+	partitionLows := make([]int, count)
+
+	for i := 0; i < count; i++ {
+		low := i * partSize
+		high := low + partSize
+		if i == count - 1 {
+			high = order
+		}
+		partitionLows[i] = low
+		partitionSlices[i] = vertices[low:high]
+	}
+	return partitionSlices, partitionLows
+}
+
 func intInSlice(i int, list []int) bool{
 	for _, b := range list {
 		if b == i {
@@ -0,0 +1,110 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Options selecting how SLPA runs, and the entry point taking them
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"runtime"
+)
+
+type SLPAOptions struct {
+	Iterations       int
+	Threshold        float64 // labels observed less than this fraction of the time are dropped
+	Seed             int64
+	ConcurrentCount  int
+	MinCommunitySize int
+	// Deterministic runs synchronous iterations in which every listener hears the labels as they stood at the end of the previous
+	// iteration, drawing from a random stream determined by the seed, the iteration, and the listener.  The communities then depend only
+	// on the seed, not on goroutine scheduling or ConcurrentCount.
+	Deterministic bool
+	// Weighted scales each speaker's vote by the weight of the edge joining it to the listener
+	Weighted bool
+	// Direction selects the speakers on directed networks: Outgoing (the original behavior) hears the vertices the listener points at,
+	// Incoming hears the vertices pointing at it, and AnyDirection hears both
+	Direction TraversalDirection
+	Speaker   SpeakerRule  // nil for SpeakProportional
+	Listener  ListenerRule // nil for ListenMostFrequent
+	// Partitions, if not nil, assigns the vertices to goroutines in place of ConcurrentCount contiguous ranges of the sorted vertex ids,
+	// e.g., from BFSPartitions or LabelPropagationPartitions.  Every vertex must lie in exactly one partition.
+	Partitions [][]uint32
+}
+
+func NewSLPAOptions() *SLPAOptions {
+	options := new(SLPAOptions)
+	options.Iterations = 20
+	options.Threshold = 0.3
+	options.Seed = 1
+	options.ConcurrentCount = runtime.NumCPU()
+	options.MinCommunitySize = 2
+	options.Deterministic = false
+	options.Weighted = false
+	options.Direction = Outgoing
+	return options
+}
+
+// SLPA community detection configured by options; with the default options this is ConcurrentSLPA.  Communities are keyed
+// by label as in ConcurrentSLPA; SLPADetailed returns membership strengths and statistics as well.
+func SLPAWithOptions(G *Core.Network, options *SLPAOptions) (map[int][]uint32, error) {
+	return SLPAWithOptionsContext(context.Background(), G, options, nil)
+}
+
+// As SLPAWithOptions, but stops with the context's error if ctx is cancelled or times out, and reports progress if progress is not nil.
+// The synchronous mode reports once per iteration for the whole network; the asynchronous mode reports per partition.
+func SLPAWithOptionsContext(ctx context.Context, G *Core.Network, options *SLPAOptions, progress ProgressFunc) (map[int][]uint32, error) {
+	if err := checkSLPAOptions(G, options); err != nil {
+		return nil, err
+	}
+	if G.Order() == 0 {
+		return make(map[int][]uint32), nil
+	}
+
+	vertices, memory, _, err := runSLPA(ctx, G, options, newProgressReporter("SLPAWithOptionsContext", progress))
+	if err != nil {
+		return nil, err
+	}
+	return communitiesFromMemory(vertices, memory, options.Threshold, options.MinCommunitySize), nil
+}
+
+func checkSLPAOptions(G *Core.Network, options *SLPAOptions) error {
+	if G == nil {
+		return Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if options == nil {
+		return Core.NewNetworkArgumentNullError("Options must be non-null")
+	}
+	if options.Iterations < 1 {
+		return Core.NewNetworkArgumentError("SLPA requires at least one iteration")
+	}
+	if options.Threshold < 0 || options.Threshold > 1 {
+		return Core.NewNetworkArgumentError("Threshold must lie between 0 and 1")
+	}
+	if options.ConcurrentCount < 1 {
+		return Core.NewNetworkArgumentError("ConcurrentCount must be at least 1")
+	}
+	if options.Partitions != nil {
+		return ValidatePartitions(G, options.Partitions)
+	}
+	return nil
+}
@@ -0,0 +1,299 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Detailed SLPA results: membership strengths, the communities as an inverse index, per-iteration statistics, and the label
+// observations themselves, so that a run can be thresholded again without repeating the propagation
+
+package Algorithms
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"io"
+	"sort"
+	"sync"
+)
+
+type SLPAIterationStats struct {
+	Iteration      int `json:"iteration"`
+	Listeners      int `json:"listeners"`      // listeners that recorded a label
+	Changed        int `json:"changed"`        // listeners that recorded a label other than the one they had observed most often
+	DistinctLabels int `json:"distinctLabels"` // distinct labels recorded
+}
+
+// Communities are numbered from 0 in order of their lowest member vertex id; Origins gives the vertex whose initial label each carries.
+// Memberships[v][c] is the fraction of v's observations that were the label of community c.  Observations[v][u] counts the times v
+// observed the label originating at u.
+type SLPAResult struct {
+	Threshold        float64                    `json:"threshold"`
+	MinCommunitySize int                        `json:"minCommunitySize"`
+	Communities      map[int][]uint32           `json:"communities"`
+	Origins          map[int]uint32             `json:"origins"`
+	Memberships      map[uint32]map[int]float64 `json:"memberships"`
+	Observations     map[uint32]map[uint32]int  `json:"observations"`
+	Iterations       []SLPAIterationStats       `json:"iterations"`
+}
+
+// Runs SLPA as SLPAWithOptions does and returns the detailed result
+func SLPADetailed(G *Core.Network, options *SLPAOptions) (*SLPAResult, error) {
+	return SLPADetailedContext(context.Background(), G, options, nil)
+}
+
+// As SLPADetailed, but stops with the context's error if ctx is cancelled or times out, and reports progress if progress is not nil
+func SLPADetailedContext(ctx context.Context, G *Core.Network, options *SLPAOptions, progress ProgressFunc) (*SLPAResult, error) {
+	if err := checkSLPAOptions(G, options); err != nil {
+		return nil, err
+	}
+
+	retVal := new(SLPAResult)
+	retVal.Observations = make(map[uint32]map[uint32]int, G.Order())
+	retVal.Iterations = make([]SLPAIterationStats, 0)
+	if G.Order() > 0 {
+		vertices, memory, stats, err := runSLPA(ctx, G, options, newProgressReporter("SLPADetailedContext", progress))
+		if err != nil {
+			return nil, err
+		}
+		for idx, m := range memory {
+			observed := make(map[uint32]int, len(m.labels))
+			for i, label := range m.labels {
+				observed[vertices[label]] = m.counts[i]
+			}
+			retVal.Observations[vertices[idx]] = observed
+		}
+		retVal.Iterations = stats
+	}
+	return retVal.Rethreshold(options.Threshold, options.MinCommunitySize)
+}
+
+// Returns a result with the communities and memberships recomputed from the observations for a new threshold and minimum community size
+func (result *SLPAResult) Rethreshold(threshold float64, minCommunitySize int) (*SLPAResult, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, Core.NewNetworkArgumentError("Threshold must lie between 0 and 1")
+	}
+
+	retVal := new(SLPAResult)
+	retVal.Threshold = threshold
+	retVal.MinCommunitySize = minCommunitySize
+	retVal.Observations = result.Observations
+	retVal.Iterations = result.Iterations
+
+	vertices := make([]uint32, 0, len(result.Observations))
+	for v := range result.Observations {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool { return vertices[i] < vertices[j] })
+
+	// members of each surviving label, ascending, and the strength of each membership
+	members := make(map[uint32][]uint32)
+	strength := make(map[uint32]map[uint32]float64, len(vertices))
+	for _, v := range vertices {
+		total := 0
+		for _, count := range result.Observations[v] {
+			total += count
+		}
+		cutoff := int(float64(total) * threshold)
+		strength[v] = make(map[uint32]float64)
+		for origin, count := range result.Observations[v] {
+			if count >= cutoff {
+				members[origin] = append(members[origin], v)
+				strength[v][origin] = float64(count) / float64(total)
+			}
+		}
+	}
+
+	origins := make([]uint32, 0, len(members))
+	for origin, list := range members {
+		if minCommunitySize <= 1 || len(list) >= minCommunitySize {
+			origins = append(origins, origin)
+		}
+	}
+	sort.Slice(origins, func(i, j int) bool {
+		a, b := members[origins[i]][0], members[origins[j]][0]
+		if a != b {
+			return a < b
+		}
+		return origins[i] < origins[j]
+	})
+
+	retVal.Communities = make(map[int][]uint32, len(origins))
+	retVal.Origins = make(map[int]uint32, len(origins))
+	retVal.Memberships = make(map[uint32]map[int]float64, len(vertices))
+	for id, origin := range origins {
+		retVal.Communities[id] = members[origin]
+		retVal.Origins[id] = origin
+		for _, v := range members[origin] {
+			if _, ok := retVal.Memberships[v]; !ok {
+				retVal.Memberships[v] = make(map[int]float64)
+			}
+			retVal.Memberships[v][id] = strength[v][origin]
+		}
+	}
+	return retVal, nil
+}
+
+func (result *SLPAResult) ListJSON(writer *bufio.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(result)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func ReadSLPAResultJSON(reader io.Reader) (*SLPAResult, error) {
+	retVal := new(SLPAResult)
+	err := json.NewDecoder(reader).Decode(retVal)
+	if err != nil {
+		return nil, err
+	}
+	return retVal, nil
+}
+
+// Writes one row per membership, vertex,community,origin,weight, ordered by vertex and then community
+func (result *SLPAResult) ListCSV(writer *bufio.Writer) error {
+	_, err := Fprintln(writer, "vertex,community,origin,weight")
+	if err != nil {
+		return err
+	}
+	vertices := make([]uint32, 0, len(result.Memberships))
+	for v := range result.Memberships {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool { return vertices[i] < vertices[j] })
+	for _, v := range vertices {
+		ids := make([]int, 0, len(result.Memberships[v]))
+		for id := range result.Memberships[v] {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			_, err = Fprintf(writer, "%d,%d,%d,%.6f\n", v, id, result.Origins[id], result.Memberships[v][id])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Flush()
+}
+
+// runs either implementation, returning the vertices in ascending order, their label memories (labels are indices into vertices),
+// and the statistics of each iteration
+func runSLPA(ctx context.Context, G *Core.Network, options *SLPAOptions, reporter *progressReporter) ([]uint32, []*labelMemory, []SLPAIterationStats, error) {
+	if options.Deterministic {
+		return synchronousSLPA(ctx, G, options, reporter)
+	}
+
+	stats := newSLPAStats(options.Iterations)
+	nodeLabels, err := concurrentSLPA(ctx, G, options.Iterations, options.Seed, slpaPartitions(G, options), newSLPARules(options), stats, reporter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	vertices := G.Vertices(true)
+	memory := make([]*labelMemory, len(vertices))
+	for idx, vert := range vertices {
+		labelMap, _ := nodeLabels.Load(vert)
+		labels, counts := memorySnapshot(labelMap.(*sync.Map))
+		memory[idx] = &labelMemory{labels: labels, counts: counts}
+		for _, count := range counts {
+			memory[idx].total += count
+		}
+	}
+	return vertices, memory, stats.list(), nil
+}
+
+// the partitions in options, or ConcurrentCount contiguous ranges of the sorted vertices
+func slpaPartitions(G *Core.Network, options *SLPAOptions) [][]uint32 {
+	return vertexPartitions(G, options.Partitions, options.ConcurrentCount)
+}
+
+// partitions if not nil, otherwise count contiguous ranges of the sorted vertices (fewer if there are fewer vertices)
+func vertexPartitions(G *Core.Network, partitions [][]uint32, count int) [][]uint32 {
+	if partitions != nil {
+		return partitions
+	}
+	if count > G.Order() {
+		count = G.Order()
+	}
+	retVal, _ := ContiguousPartitions(G.Vertices(true), count)
+	return retVal
+}
+
+// per-iteration counts gathered from concurrently running partitions
+type slpaStats struct {
+	iterations []*iterationCounter
+}
+
+type iterationCounter struct {
+	lock      sync.Mutex
+	listeners int
+	changed   int
+	labels    map[int]bool
+}
+
+func newSLPAStats(iterations int) *slpaStats {
+	stats := new(slpaStats)
+	stats.iterations = make([]*iterationCounter, iterations)
+	for i := range stats.iterations {
+		stats.iterations[i] = &iterationCounter{labels: make(map[int]bool)}
+	}
+	return stats
+}
+
+// the counter for iteration i, numbered from 0; nil when statistics are not being collected
+func (stats *slpaStats) at(i int) *iterationCounter {
+	if stats == nil {
+		return nil
+	}
+	return stats.iterations[i]
+}
+
+func (counter *iterationCounter) record(label int, previous int) {
+	counter.lock.Lock()
+	defer counter.lock.Unlock()
+	counter.listeners++
+	if label != previous {
+		counter.changed++
+	}
+	counter.labels[label] = true
+}
+
+func (stats *slpaStats) list() []SLPAIterationStats {
+	retVal := make([]SLPAIterationStats, len(stats.iterations))
+	for i, counter := range stats.iterations {
+		retVal[i] = SLPAIterationStats{Iteration: i + 1, Listeners: counter.listeners, Changed: counter.changed, DistinctLabels: len(counter.labels)}
+	}
+	return retVal
+}
+
+// the label observed most often, ties going to the lowest label
+func mostFrequentLabel(labels []int, counts []int) int {
+	best := 0
+	for i, count := range counts {
+		if count > counts[best] {
+			best = i
+		}
+	}
+	return labels[best]
+}
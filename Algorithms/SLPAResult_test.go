@@ -0,0 +1,132 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSLPADetailed(t *testing.T) {
+	G := makeTwoCliques()
+	for _, deterministic := range []bool{true, false} {
+		options := NewSLPAOptions()
+		options.Deterministic = deterministic
+		options.Iterations = 30
+		options.ConcurrentCount = 2
+		result, err := SLPADetailed(G, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Iterations) != options.Iterations {
+			t.Fatalf("Expected %d iteration records, found %d", options.Iterations, len(result.Iterations))
+		}
+		for _, stats := range result.Iterations {
+			if stats.Listeners != G.Order() || stats.Changed > stats.Listeners || stats.DistinctLabels < 1 {
+				t.Errorf("Implausible statistics %+v", stats)
+			}
+		}
+		for v, observed := range result.Observations {
+			total := 0
+			for _, count := range observed {
+				total += count
+			}
+			// the initial label plus one per iteration
+			if total != options.Iterations+1 {
+				t.Errorf("Vertex %d has %d observations, expected %d", v, total, options.Iterations+1)
+			}
+		}
+		for id, members := range result.Communities {
+			for _, v := range members {
+				if w := result.Memberships[v][id]; w <= 0 || w > 1 {
+					t.Errorf("Membership of %d in %d has strength %f", v, id, w)
+				}
+			}
+		}
+
+		expected, _ := SLPAWithOptions(G, options)
+		if deterministic && len(expected) != len(result.Communities) {
+			t.Errorf("Expected %d communities as from SLPAWithOptions, found %d", len(expected), len(result.Communities))
+		}
+	}
+}
+
+func TestSLPARethreshold(t *testing.T) {
+	options := NewSLPAOptions()
+	options.Deterministic = true
+	result, err := SLPADetailed(makeTwoCliques(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// at threshold 0 every observed label survives, so there are at least as many memberships
+	loose, _ := result.Rethreshold(0, 1)
+	for v, memberships := range result.Memberships {
+		if len(loose.Memberships[v]) < len(memberships) {
+			t.Errorf("Vertex %d lost memberships when the threshold was lowered", v)
+		}
+	}
+	total := 0.0
+	for _, w := range loose.Memberships[0] {
+		total += w
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("Expected the strengths of vertex 0 to sum to 1 at threshold 0, found %f", total)
+	}
+	if _, err = result.Rethreshold(1.5, 2); err == nil {
+		t.Error("Expected an error for a threshold above 1")
+	}
+}
+
+func TestSLPAResultSerialization(t *testing.T) {
+	options := NewSLPAOptions()
+	options.Deterministic = true
+	result, _ := SLPADetailed(makeTwoCliques(), options)
+
+	var buffer bytes.Buffer
+	if err := result.ListJSON(bufio.NewWriter(&buffer)); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadSLPAResultJSON(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read, result) {
+		t.Error("Result read from JSON differs from the result written")
+	}
+
+	buffer.Reset()
+	if err = result.ListCSV(bufio.NewWriter(&buffer)); err != nil {
+		t.Fatal(err)
+	}
+	rows := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	count := 0
+	for _, memberships := range result.Memberships {
+		count += len(memberships)
+	}
+	if rows[0] != "vertex,community,origin,weight" || len(rows) != count+1 {
+		t.Errorf("Expected a header and %d rows, found %d lines", count, len(rows))
+	}
+}
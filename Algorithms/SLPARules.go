@@ -0,0 +1,147 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Speaker and listener rules for SLPA
+// In each iteration a listener hears one label from each of its speakers and adds one label to its memory.  Which vertices speak, how a
+// speaker picks the label it sends, how much each label counts, and how the listener chooses among what it heard are set by SLPAOptions.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+	"sync"
+)
+
+// the random numbers available to rules; *rand.Rand satisfies it
+type SLPARandom interface {
+	Intn(n int) int
+	Float64() float64
+}
+
+// Chooses the label a speaker sends from its memory: labels in ascending order and the number of times each has been observed
+type SpeakerRule func(labels []int, counts []int, r SLPARandom) int
+
+// Chooses the label a listener records from the labels heard and their votes
+type ListenerRule func(heard map[int]float64, r SLPARandom) int
+
+// Sends a label drawn with probability proportional to how often the speaker has observed it, as in the original SLPA
+func SpeakProportional(labels []int, counts []int, r SLPARandom) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	roll := r.Intn(total)
+	for i, count := range counts {
+		if roll < count {
+			return labels[i]
+		}
+		roll -= count
+	}
+	return labels[len(labels)-1]
+}
+
+// Sends the label the speaker has observed most often, ties going to the lowest label
+func SpeakMostFrequent(labels []int, counts []int, r SLPARandom) int {
+	best := 0
+	for i, count := range counts {
+		if count > counts[best] {
+			best = i
+		}
+	}
+	return labels[best]
+}
+
+// Records the label with the most votes, breaking ties at random
+func ListenMostFrequent(heard map[int]float64, r SLPARandom) int {
+	best := 0.0
+	tied := make([]int, 0, 1)
+	for label, votes := range heard {
+		if len(tied) == 0 || votes > best {
+			best = votes
+			tied = tied[:0]
+			tied = append(tied, label)
+		} else if votes == best {
+			tied = append(tied, label)
+		}
+	}
+	if len(tied) == 1 {
+		return tied[0]
+	}
+	sort.Ints(tied)
+	return tied[r.Intn(len(tied))]
+}
+
+type slpaRules struct {
+	direction TraversalDirection
+	weighted  bool
+	speaker   SpeakerRule
+	listener  ListenerRule
+}
+
+// the behavior of the original implementation: unweighted, hearing the vertices the listener points at
+func defaultSLPARules() *slpaRules {
+	return &slpaRules{direction: Outgoing, speaker: SpeakProportional, listener: ListenMostFrequent}
+}
+
+func newSLPARules(options *SLPAOptions) *slpaRules {
+	rules := defaultSLPARules()
+	rules.direction = options.Direction
+	rules.weighted = options.Weighted
+	if options.Speaker != nil {
+		rules.speaker = options.Speaker
+	}
+	if options.Listener != nil {
+		rules.listener = options.Listener
+	}
+	return rules
+}
+
+// the vertices a listener hears, in ascending order, with the weights of the edges joining them
+func (rules *slpaRules) speakers(G *Core.Network, listener uint32) []traversalEdge {
+	return traversalNeighbors(G, listener, rules.direction)
+}
+
+func (rules *slpaRules) vote(weight float32) float64 {
+	if rules.weighted {
+		return float64(weight)
+	}
+	return 1.0
+}
+
+// the labels in a concurrent label memory in ascending order, with their counts
+func memorySnapshot(m *sync.Map) ([]int, []int) {
+	observed := make(map[int]int)
+	m.Range(func(k, v interface{}) bool {
+		observed[k.(int)] = v.(int)
+		return true
+	})
+	labels := make([]int, 0, len(observed))
+	for label := range observed {
+		labels = append(labels, label)
+	}
+	sort.Ints(labels)
+	counts := make([]int, len(labels))
+	for i, label := range labels {
+		counts[i] = observed[label]
+	}
+	return labels, counts
+}
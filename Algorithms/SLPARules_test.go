@@ -0,0 +1,101 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"reflect"
+	"testing"
+)
+
+func TestSLPAWeightedListening(t *testing.T) {
+	// vertex 0 hears 1 over a heavy edge and 2 and 3 over light ones; after one iteration every speaker has sent its own label
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(0, 1, 5.0)
+	_ = G.AddEdge(0, 2, 1.0)
+	_ = G.AddEdge(0, 3, 1.0)
+
+	options := NewSLPAOptions()
+	options.Deterministic = true
+	options.Iterations = 1
+	options.Threshold = 0.5
+	options.MinCommunitySize = 1
+	options.Weighted = true
+	communities, err := SLPAWithOptions(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(communities[1], []uint32{0, 1}) {
+		t.Errorf("Expected vertex 0 to take the label of its heaviest neighbor, found %v", communities)
+	}
+
+	// a pluggable listener taking the label with the fewest votes, lowest first
+	options.Listener = func(heard map[int]float64, r SLPARandom) int {
+		fewest := -1
+		for label, votes := range heard {
+			if fewest == -1 || votes < heard[fewest] || (votes == heard[fewest] && label < fewest) {
+				fewest = label
+			}
+		}
+		return fewest
+	}
+	communities, _ = SLPAWithOptions(G, options)
+	if !reflect.DeepEqual(communities[2], []uint32{0, 2}) {
+		t.Errorf("Expected the custom listener to pick label 2 at vertex 0, found %v", communities)
+	}
+}
+
+func TestSLPADirection(t *testing.T) {
+	// a hub pointing at five leaves
+	G := Core.NewNetwork(true)
+	for i := uint32(1); i <= 5; i++ {
+		_ = G.AddEdge(0, i, 1.0)
+	}
+
+	options := NewSLPAOptions()
+	options.Deterministic = true
+	options.Iterations = 10
+	options.Direction = Incoming
+	communities, err := SLPAWithOptions(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(communities[0], []uint32{0, 1, 2, 3, 4, 5}) {
+		t.Errorf("Expected leaves listening to their source to join the hub, found %v", communities)
+	}
+
+	// hearing only the vertices they point at, the leaves hear nothing and keep their own labels
+	options.Direction = Outgoing
+	communities, _ = SLPAWithOptions(G, options)
+	if len(communities[0]) > 1 {
+		t.Errorf("Expected no leaf to adopt the hub's label, found %v", communities)
+	}
+
+	// the asynchronous implementation honors the same options
+	options.Deterministic = false
+	options.Direction = Incoming
+	options.ConcurrentCount = 2
+	communities, _ = SLPAWithOptions(G, options)
+	if len(communities[0]) != 6 {
+		t.Errorf("Expected the asynchronous run to put every leaf with the hub, found %v", communities)
+	}
+}
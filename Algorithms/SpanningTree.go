@@ -0,0 +1,341 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Minimum and maximum spanning forests of undirected networks (Kruskal and Prim) and optimum spanning arborescences of directed networks (Chu-Liu/Edmonds)
+// Ties between equal weights are broken by vertex id, so the forest returned does not depend on map iteration order.
+
+package Algorithms
+
+import (
+	"container/heap"
+	"context"
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+)
+
+// Returns a spanning forest of minimum (or, if maximum is true, maximum) total weight found by Kruskal's algorithm.  The forest is a new
+// undirected network holding every vertex of G.
+func KruskalSpanningForest(G *Core.Network, maximum bool) (*Core.Network, error) {
+	if err := checkSpanningInput(G); err != nil {
+		return nil, err
+	}
+
+	edges := make([]Edge, 0, G.Size())
+	for _, u := range G.Vertices(false) {
+		for v, wt := range G.GetNeighbors(u) {
+			if u < v {
+				edges = append(edges, Edge{From: u, To: v, Weight: wt})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return spanningLess(edges[i], edges[j], maximum) })
+
+	retVal := newForest(G)
+	added := 0
+	parent := make(map[uint32]uint32, G.Order())
+	rank := make(map[uint32]int, G.Order())
+	find := func(v uint32) uint32 {
+		for {
+			p, ok := parent[v]
+			if !ok || p == v {
+				return v
+			}
+			// path halving
+			if gp, ok := parent[p]; ok {
+				parent[v] = gp
+			}
+			v = p
+		}
+	}
+
+	for _, edge := range edges {
+		ru := find(edge.From)
+		rv := find(edge.To)
+		if ru == rv {
+			continue
+		}
+		if rank[ru] < rank[rv] {
+			ru, rv = rv, ru
+		}
+		parent[rv] = ru
+		if rank[ru] == rank[rv] {
+			rank[ru]++
+		}
+		_ = retVal.AddEdge(edge.From, edge.To, edge.Weight)
+		added++
+		if added == G.Order()-1 {
+			break
+		}
+	}
+	return retVal, nil
+}
+
+// Returns a spanning forest of minimum (or, if maximum is true, maximum) total weight found by Prim's algorithm, growing a tree from the
+// lowest unreached vertex id in each component.  The forest is a new undirected network holding every vertex of G.
+func PrimSpanningForest(G *Core.Network, maximum bool) (*Core.Network, error) {
+	if err := checkSpanningInput(G); err != nil {
+		return nil, err
+	}
+
+	retVal := newForest(G)
+	inTree := make(map[uint32]bool, G.Order())
+	for _, root := range G.Vertices(true) {
+		if inTree[root] {
+			continue
+		}
+		inTree[root] = true
+		frontier := &edgeHeap{maximum: maximum}
+		for v, wt := range G.GetNeighbors(root) {
+			heap.Push(frontier, Edge{From: root, To: v, Weight: wt})
+		}
+		for frontier.Len() > 0 {
+			edge := heap.Pop(frontier).(Edge)
+			if inTree[edge.To] {
+				continue
+			}
+			inTree[edge.To] = true
+			_ = retVal.AddEdge(edge.From, edge.To, edge.Weight)
+			for v, wt := range G.GetNeighbors(edge.To) {
+				if !inTree[v] {
+					heap.Push(frontier, Edge{From: edge.To, To: v, Weight: wt})
+				}
+			}
+		}
+	}
+	return retVal, nil
+}
+
+// Returns the spanning arborescence rooted at root with minimum (or, if maximum is true, maximum) total weight, found by the algorithm of
+// Chu and Liu and of Edmonds.  The arborescence is a new directed network in which every vertex but the root has exactly one incoming edge.
+// An error is returned if some vertex cannot be reached from root.
+func ChuLiuEdmondsArborescence(G *Core.Network, root uint32, maximum bool) (*Core.Network, error) {
+	return ChuLiuEdmondsArborescenceContext(context.Background(), G, root, maximum, nil)
+}
+
+// As ChuLiuEdmondsArborescence, but stops with the context's error if ctx is cancelled, and reports progress, if not nil, as each level of
+// cycle contraction is entered
+func ChuLiuEdmondsArborescenceContext(ctx context.Context, G *Core.Network, root uint32, maximum bool, progress ProgressFunc) (*Core.Network, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if !G.Directed() {
+		return nil, Core.NewNetworkArgumentError("Arborescences require a directed network; use a spanning forest for undirected networks")
+	}
+	if !G.HasVertex(root) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Root %d is not in the network", root))
+	}
+
+	vertices := G.Vertices(true)
+	index := make(map[uint32]int, len(vertices))
+	for i, v := range vertices {
+		index[v] = i
+	}
+	original := make([]Edge, 0, G.Size())
+	edges := make([]arborescenceEdge, 0, G.Size())
+	for _, u := range vertices {
+		for _, n := range traversalNeighbors(G, u, Outgoing) {
+			wt := float64(n.weight)
+			if maximum {
+				wt = -wt
+			}
+			edges = append(edges, arborescenceEdge{from: index[u], to: index[n.to], weight: wt, id: len(original)})
+			original = append(original, Edge{From: u, To: n.to, Weight: n.weight})
+		}
+	}
+
+	chosen, ok := chuLiuEdmonds(ctx, len(vertices), index[root], edges, newProgressReporter("ChuLiuEdmondsArborescenceContext", progress), 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Not every vertex is reachable from root %d", root))
+	}
+
+	retVal := Core.NewNetwork(true)
+	for _, v := range vertices {
+		retVal.AddVertex(v)
+	}
+	for _, i := range chosen {
+		_ = retVal.AddEdge(original[i].From, original[i].To, original[i].Weight)
+	}
+	return retVal, nil
+}
+
+type arborescenceEdge struct {
+	from   int
+	to     int
+	weight float64
+	id     int // index of the edge in the level above
+}
+
+// minimum arborescence of vertices 0..n-1 rooted at root; returns the ids of the chosen edges, contracting cycles recursively.  level counts
+// the contractions; returns false at once if ctx is cancelled.
+func chuLiuEdmonds(ctx context.Context, n int, root int, edges []arborescenceEdge, reporter *progressReporter, level int) ([]int, bool) {
+	if cancelled(ctx) {
+		return nil, false
+	}
+	reporter.emit(level, -1)
+
+	// cheapest edge entering each vertex, by position in edges
+	minIn := make([]int, n)
+	for v := range minIn {
+		minIn[v] = -1
+	}
+	for i, edge := range edges {
+		if edge.to == root || edge.from == edge.to {
+			continue
+		}
+		if minIn[edge.to] == -1 || edge.weight < edges[minIn[edge.to]].weight {
+			minIn[edge.to] = i
+		}
+	}
+	for v := 0; v < n; v++ {
+		if v != root && minIn[v] == -1 {
+			return nil, false
+		}
+	}
+
+	// find the cycles formed by the cheapest edges and give every vertex its component in the contracted graph
+	component := make([]int, n)
+	cycleOf := make([]int, n)
+	mark := make([]int, n)
+	for v := range component {
+		component[v] = -1
+		cycleOf[v] = -1
+		mark[v] = -1
+	}
+	cycles := 0
+	for start := 0; start < n; start++ {
+		v := start
+		for v != root && mark[v] == -1 {
+			mark[v] = start
+			v = edges[minIn[v]].from
+		}
+		if v != root && mark[v] == start && cycleOf[v] == -1 {
+			for u := v; cycleOf[u] == -1; u = edges[minIn[u]].from {
+				cycleOf[u] = cycles
+			}
+			cycles++
+		}
+	}
+	if cycles == 0 {
+		retVal := make([]int, 0, n-1)
+		for v := 0; v < n; v++ {
+			if v != root {
+				retVal = append(retVal, edges[minIn[v]].id)
+			}
+		}
+		return retVal, true
+	}
+
+	next := cycles
+	for v := 0; v < n; v++ {
+		if cycleOf[v] != -1 {
+			component[v] = cycleOf[v]
+		} else {
+			component[v] = next
+			next++
+		}
+	}
+
+	// entering a cycle at v replaces the cycle edge into v, so its cost is reduced by that edge's weight
+	contracted := make([]arborescenceEdge, 0, len(edges))
+	for i, edge := range edges {
+		cu := component[edge.from]
+		cv := component[edge.to]
+		if cu == cv {
+			continue
+		}
+		wt := edge.weight
+		if cycleOf[edge.to] != -1 {
+			wt -= edges[minIn[edge.to]].weight
+		}
+		contracted = append(contracted, arborescenceEdge{from: cu, to: cv, weight: wt, id: i})
+	}
+
+	chosen, ok := chuLiuEdmonds(ctx, next, component[root], contracted, reporter, level+1)
+	if !ok {
+		return nil, false
+	}
+
+	retVal := make([]int, 0, n-1)
+	entered := make(map[int]bool)
+	for _, i := range chosen {
+		edge := edges[i]
+		retVal = append(retVal, edge.id)
+		if cycleOf[edge.to] != -1 {
+			entered[edge.to] = true
+		}
+	}
+	// keep every cycle edge except the one into the vertex where the cycle is entered
+	for v := 0; v < n; v++ {
+		if cycleOf[v] != -1 && !entered[v] {
+			retVal = append(retVal, edges[minIn[v]].id)
+		}
+	}
+	return retVal, true
+}
+
+func checkSpanningInput(G *Core.Network) error {
+	if G == nil {
+		return Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if G.Directed() {
+		return Core.NewNetworkArgumentError("Spanning forests require an undirected network; use ChuLiuEdmondsArborescence for directed networks")
+	}
+	return nil
+}
+
+func newForest(G *Core.Network) *Core.Network {
+	retVal := Core.NewNetwork(false)
+	for _, v := range G.Vertices(false) {
+		retVal.AddVertex(v)
+	}
+	return retVal
+}
+
+// orders by weight, ascending for minimum and descending for maximum, then by endpoints
+func spanningLess(a Edge, b Edge, maximum bool) bool {
+	if a.Weight != b.Weight {
+		return (a.Weight < b.Weight) != maximum
+	}
+	if a.From != b.From {
+		return a.From < b.From
+	}
+	return a.To < b.To
+}
+
+// priority queue of edges for Prim's algorithm
+type edgeHeap struct {
+	edges   []Edge
+	maximum bool
+}
+
+func (h *edgeHeap) Len() int           { return len(h.edges) }
+func (h *edgeHeap) Less(i, j int) bool { return spanningLess(h.edges[i], h.edges[j], h.maximum) }
+func (h *edgeHeap) Swap(i, j int)      { h.edges[i], h.edges[j] = h.edges[j], h.edges[i] }
+func (h *edgeHeap) Push(x interface{}) { h.edges = append(h.edges, x.(Edge)) }
+func (h *edgeHeap) Pop() interface{} {
+	last := h.edges[len(h.edges)-1]
+	h.edges = h.edges[:len(h.edges)-1]
+	return last
+}
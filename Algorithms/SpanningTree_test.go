@@ -0,0 +1,179 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func totalWeight(G *Core.Network) float64 {
+	sum := 0.0
+	for _, u := range G.Vertices(false) {
+		for v, wt := range G.GetNeighbors(u) {
+			if G.Directed() || u < v {
+				sum += float64(wt)
+			}
+		}
+	}
+	return sum
+}
+
+func randomWeighted(n int, p float64, directed bool, r *rand.Rand) *Core.Network {
+	G := Core.NewNetwork(directed)
+	for u := 0; u < n; u++ {
+		G.AddVertex(uint32(u))
+		for v := 0; v < n; v++ {
+			if u != v && (directed || u < v) && r.Float64() < p {
+				_ = G.AddEdge(uint32(u), uint32(v), float32(1+r.Intn(20)))
+			}
+		}
+	}
+	return G
+}
+
+func TestSpanningForest(t *testing.T) {
+	G := Core.NewNetwork(false)
+	_ = G.AddEdge(1, 2, 1.0)
+	_ = G.AddEdge(2, 3, 2.0)
+	_ = G.AddEdge(1, 3, 3.0)
+	_ = G.AddEdge(3, 4, 4.0)
+	_ = G.AddEdge(5, 6, 5.0)
+
+	for _, build := range []func(*Core.Network, bool) (*Core.Network, error){KruskalSpanningForest, PrimSpanningForest} {
+		F, err := build(G, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if F.Order() != 6 || F.Size() != 4 || totalWeight(F) != 12.0 {
+			t.Errorf("Expected a minimum forest of 4 edges and weight 12, found %d edges and weight %f", F.Size(), totalWeight(F))
+		}
+		F, _ = build(G, true)
+		if F.Size() != 4 || totalWeight(F) != 14.0 || !F.HasEdge(1, 3) {
+			t.Errorf("Expected a maximum forest of 4 edges and weight 14, found %d edges and weight %f", F.Size(), totalWeight(F))
+		}
+	}
+
+	r := rand.New(rand.NewSource(34))
+	for trial := 0; trial < 20; trial++ {
+		H := randomWeighted(30, 0.15, false, r)
+		for _, maximum := range []bool{false, true} {
+			K, _ := KruskalSpanningForest(H, maximum)
+			P, _ := PrimSpanningForest(H, maximum)
+			if K.Size() != P.Size() || math.Abs(totalWeight(K)-totalWeight(P)) > 1e-9 {
+				t.Errorf("Kruskal and Prim disagree: %d edges weight %f versus %d edges weight %f", K.Size(), totalWeight(K), P.Size(), totalWeight(P))
+			}
+		}
+	}
+
+	if _, err := KruskalSpanningForest(Core.NewNetwork(true), false); err == nil {
+		t.Errorf("Expected an error for a directed network")
+	}
+}
+
+// the optimum over every choice of one incoming edge per non-root vertex that yields a tree
+func bruteArborescence(G *Core.Network, root uint32, maximum bool) (float64, bool) {
+	vertices := G.Vertices(true)
+	best := math.Inf(1)
+	if maximum {
+		best = math.Inf(-1)
+	}
+	found := false
+	choice := make(map[uint32]uint32)
+	var search func(i int, weight float64)
+	search = func(i int, weight float64) {
+		if i == len(vertices) {
+			for _, v := range vertices {
+				// every vertex must reach the root by following its chosen edge
+				steps := 0
+				for u := v; u != root; u = choice[u] {
+					steps++
+					if steps > len(vertices) {
+						return
+					}
+				}
+			}
+			found = true
+			if (!maximum && weight < best) || (maximum && weight > best) {
+				best = weight
+			}
+			return
+		}
+		v := vertices[i]
+		if v == root {
+			search(i+1, weight)
+			return
+		}
+		for u, wt := range G.GetSources(v) {
+			choice[v] = u
+			search(i+1, weight+float64(wt))
+		}
+	}
+	search(0, 0)
+	return best, found
+}
+
+func TestArborescence(t *testing.T) {
+	// the cheapest edges into 2 and 3 form a cycle that must be broken
+	G := Core.NewNetwork(true)
+	_ = G.AddEdge(1, 2, 10.0)
+	_ = G.AddEdge(1, 3, 8.0)
+	_ = G.AddEdge(2, 3, 1.0)
+	_ = G.AddEdge(3, 2, 1.0)
+	_ = G.AddEdge(3, 4, 2.0)
+	A, err := ChuLiuEdmondsArborescence(G, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if A.Size() != 3 || totalWeight(A) != 11.0 || !A.HasEdge(1, 3) || !A.HasEdge(3, 2) {
+		t.Errorf("Expected arborescence 1->3, 3->2, 3->4 of weight 11, found weight %f", totalWeight(A))
+	}
+
+	r := rand.New(rand.NewSource(35))
+	for trial := 0; trial < 40; trial++ {
+		H := randomWeighted(6, 0.45, true, r)
+		for _, maximum := range []bool{false, true} {
+			expected, ok := bruteArborescence(H, 0, maximum)
+			A, err := ChuLiuEdmondsArborescence(H, 0, maximum)
+			if ok != (err == nil) {
+				t.Fatalf("Expected existence %v, found error %v", ok, err)
+			}
+			if !ok {
+				continue
+			}
+			for _, v := range A.Vertices(false) {
+				if v != 0 && A.InDegree(v) != 1 {
+					t.Errorf("Vertex %d has in-degree %d in the arborescence", v, A.InDegree(v))
+				}
+			}
+			if math.Abs(totalWeight(A)-expected) > 1e-9 {
+				t.Errorf("Expected optimum arborescence weight %f, found %f", expected, totalWeight(A))
+			}
+		}
+	}
+
+	if _, err := ChuLiuEdmondsArborescence(G, 4, false); err == nil {
+		t.Errorf("Expected an error when the root cannot reach every vertex")
+	}
+}
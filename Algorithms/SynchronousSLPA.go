@@ -0,0 +1,190 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Deterministic, synchronous SLPA
+// Each iteration has two phases.  First every listener, concurrently by partition, hears one label from the memory of each
+// speaker and picks one; memories are only read.  Then the chosen labels are added to the memories.  Random numbers come
+// from a stream seeded by the seed, the iteration, and the listener, so neither partitioning nor scheduling affects the result.
+
+package Algorithms
+
+import (
+	"context"
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+	"sync"
+)
+
+// labels a vertex has observed, sorted by label, with their counts
+type labelMemory struct {
+	labels []int
+	counts []int
+	total  int
+}
+
+func newLabelMemory(label int) *labelMemory {
+	return &labelMemory{labels: []int{label}, counts: []int{1}, total: 1}
+}
+
+func (m *labelMemory) observe(label int) {
+	i := sort.SearchInts(m.labels, label)
+	if i < len(m.labels) && m.labels[i] == label {
+		m.counts[i]++
+	} else {
+		m.labels = append(m.labels, 0)
+		m.counts = append(m.counts, 0)
+		copy(m.labels[i+1:], m.labels[i:])
+		copy(m.counts[i+1:], m.counts[i:])
+		m.labels[i] = label
+		m.counts[i] = 1
+	}
+	m.total++
+}
+
+// splitmix64; cheap enough to create one per listener per iteration
+type streamRandom struct {
+	state uint64
+}
+
+func newStreamRandom(seed int64, iteration int, vertex int) *streamRandom {
+	r := &streamRandom{state: uint64(seed)}
+	r.state = r.next() ^ uint64(iteration)
+	r.state = r.next() ^ uint64(vertex)
+	return r
+}
+
+func (r *streamRandom) next() uint64 {
+	r.state += 0x9e3779b97f4a7c15
+	z := r.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (r *streamRandom) Intn(n int) int {
+	return int(r.next() % uint64(n))
+}
+
+func (r *streamRandom) Float64() float64 {
+	return float64(r.next()>>11) / (1 << 53)
+}
+
+// runs SLPA synchronously, returning the vertices in ascending order, the label memory of each (labels are indices into vertices),
+// and the statistics of each iteration.  Stops with the context's error if ctx is cancelled.
+func synchronousSLPA(ctx context.Context, G *Core.Network, options *SLPAOptions, reporter *progressReporter) ([]uint32, []*labelMemory, []SLPAIterationStats, error) {
+	vertices := G.Vertices(true)
+	order := len(vertices)
+	vertIdx := make(map[uint32]int, order)
+	for idx, vert := range vertices {
+		vertIdx[vert] = idx
+	}
+
+	// speakers by index, ascending, with their votes
+	rules := newSLPARules(options)
+	speakers := make([][]indexedSpeaker, order)
+	memory := make([]*labelMemory, order)
+	for idx, vert := range vertices {
+		for _, edge := range rules.speakers(G, vert) {
+			speakers[idx] = append(speakers[idx], indexedSpeaker{index: vertIdx[edge.to], vote: rules.vote(edge.weight)})
+		}
+		memory[idx] = newLabelMemory(idx)
+	}
+
+	partitionSlices := slpaPartitions(G, options)
+
+	heard := make([]int, order)
+	stats := make([]SLPAIterationStats, options.Iterations)
+	for t := 1; t <= options.Iterations; t++ {
+		var wg sync.WaitGroup
+		for _, partition := range partitionSlices {
+			wg.Add(1)
+			go func(partition []uint32) {
+				defer wg.Done()
+				for _, vert := range partition {
+					if cancelled(ctx) {
+						return
+					}
+					idx := vertIdx[vert]
+					heard[idx] = listen(speakers[idx], memory, rules, newStreamRandom(options.Seed, t, idx))
+				}
+			}(partition)
+		}
+		wg.Wait()
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+
+		recorded := make(map[int]bool)
+		stats[t-1].Iteration = t
+		for idx, label := range heard {
+			if label >= 0 {
+				stats[t-1].Listeners++
+				if label != mostFrequentLabel(memory[idx].labels, memory[idx].counts) {
+					stats[t-1].Changed++
+				}
+				recorded[label] = true
+				memory[idx].observe(label)
+			}
+		}
+		stats[t-1].DistinctLabels = len(recorded)
+		reporter.emit(t, -1)
+	}
+	return vertices, memory, stats, nil
+}
+
+type indexedSpeaker struct {
+	index int
+	vote  float64
+}
+
+// the label the listener records from what its speakers send; -1 if there are no speakers
+func listen(speakers []indexedSpeaker, memory []*labelMemory, rules *slpaRules, r *streamRandom) int {
+	if len(speakers) == 0 {
+		return -1
+	}
+	labelsSeen := make(map[int]float64)
+	for _, speaker := range speakers {
+		m := memory[speaker.index]
+		labelsSeen[rules.speaker(m.labels, m.counts, r)] += speaker.vote
+	}
+	return rules.listener(labelsSeen, r)
+}
+
+// applies the threshold as PostProcess does and groups the vertices by surviving label; members are listed in ascending order
+func communitiesFromMemory(vertices []uint32, memory []*labelMemory, threshold float64, minSize int) map[int][]uint32 {
+	communities := make(map[int][]uint32)
+	for idx, m := range memory {
+		cutoff := int(float64(m.total) * threshold)
+		for i, label := range m.labels {
+			if m.counts[i] >= cutoff {
+				communities[label] = append(communities[label], vertices[idx])
+			}
+		}
+	}
+	if minSize > 1 {
+		for label, cmty := range communities {
+			if len(cmty) < minSize {
+				delete(communities, label)
+			}
+		}
+	}
+	return communities
+}
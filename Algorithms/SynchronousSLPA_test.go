@@ -0,0 +1,87 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"github.com/smohr1824/Networks/Generators"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// two 6-cliques on 0-5 and 10-15 joined by the edge 5-10
+func makeTwoCliques() *Core.Network {
+	G := Core.NewNetwork(false)
+	for _, base := range []uint32{0, 10} {
+		for i := uint32(0); i < 6; i++ {
+			for k := i + 1; k < 6; k++ {
+				_ = G.AddEdge(base+i, base+k, 1.0)
+			}
+		}
+	}
+	_ = G.AddEdge(5, 10, 1.0)
+	return G
+}
+
+func TestDeterministicSLPA(t *testing.T) {
+	options := NewSLPAOptions()
+	options.Deterministic = true
+	options.Iterations = 40
+	options.Seed = 11
+	options.ConcurrentCount = 1
+	communities, err := SLPAWithOptions(makeTwoCliques(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for _, members := range communities {
+		if reflect.DeepEqual(members, []uint32{0, 1, 2, 3, 4, 5}) || reflect.DeepEqual(members, []uint32{10, 11, 12, 13, 14, 15}) {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expected the two cliques among the communities, found %v", communities)
+	}
+
+	G, _, _ := Generators.LFR(Generators.NewLFRParameters(300, 10, 30, 0.2), rand.New(rand.NewSource(41)))
+	options.ConcurrentCount = 1
+	first, _ := SLPAWithOptions(G, options)
+	for _, count := range []int{1, 3, 8} {
+		options.ConcurrentCount = count
+		again, _ := SLPAWithOptions(G, options)
+		if !reflect.DeepEqual(first, again) {
+			t.Errorf("Communities with %d goroutines differ from those with one", count)
+		}
+	}
+
+	options.Seed = 12
+	other, _ := SLPAWithOptions(G, options)
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("Expected a different seed to give a different run")
+	}
+
+	options.Iterations = 0
+	if _, err = SLPAWithOptions(G, options); err == nil {
+		t.Errorf("Expected an error for zero iterations")
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Breadth-first and depth-first traversal with visitor callbacks, lazy pre-order and post-order iteration, and topological sorting
+// Neighbors are visited in ascending order of vertex id, so every traversal is deterministic.
+
+package Algorithms
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+)
+
+// which edges a traversal of a directed network follows; undirected networks are traversed the same way in every direction
+type TraversalDirection int
+
+const (
+	Outgoing TraversalDirection = iota
+	Incoming
+	AnyDirection
+)
+
+// Callbacks invoked during a traversal; any may be nil.  Edges are reported in the direction of travel, from the vertex being
+// expanded to its neighbor, whatever their direction in the network.  Returning false from DiscoverVertex stops the traversal.
+type Visitor struct {
+	DiscoverVertex func(vertex uint32, depth int) bool
+	ExamineEdge    func(from uint32, to uint32, weight float32)
+	FinishVertex   func(vertex uint32)
+}
+
+type TraversalOptions struct {
+	Direction TraversalDirection
+	MaxDepth  int // vertices deeper than this are not discovered; negative for no limit
+	Visitor   *Visitor
+}
+
+func NewTraversalOptions() *TraversalOptions {
+	options := new(TraversalOptions)
+	options.Direction = Outgoing
+	options.MaxDepth = -1
+	return options
+}
+
+// Discovery and finishing times share one clock that ticks on every discovery and every finish, as in Cormen, Leiserson, Rivest and Stein.
+// Root vertices have no entry in Parent.
+type TraversalResult struct {
+	Order      []uint32          // vertices in order of discovery
+	Discovered map[uint32]int    // discovery time
+	Finished   map[uint32]int    // finishing time
+	Parent     map[uint32]uint32 // vertex from which each vertex was discovered
+	Depth      map[uint32]int    // depth in the traversal tree
+	Stopped    bool              // true if a visitor stopped the traversal early
+}
+
+type traversalEdge struct {
+	to     uint32
+	weight float32
+}
+
+// Traverses the network breadth first from start.  A vertex finishes once all of its edges have been examined.
+func BreadthFirst(G *Core.Network, start uint32, options *TraversalOptions) (*TraversalResult, error) {
+	options, err := checkTraversal(G, start, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newTraversalResult()
+	clock := 0
+	if !result.discover(start, 0, &clock, options) {
+		return result, nil
+	}
+	queue := []uint32{start}
+	for i := 0; i < len(queue); i++ {
+		v := queue[i]
+		depth := result.Depth[v]
+		for _, edge := range traversalNeighbors(G, v, options.Direction) {
+			if options.Visitor != nil && options.Visitor.ExamineEdge != nil {
+				options.Visitor.ExamineEdge(v, edge.to, edge.weight)
+			}
+			if _, seen := result.Discovered[edge.to]; seen || (options.MaxDepth >= 0 && depth+1 > options.MaxDepth) {
+				continue
+			}
+			result.Parent[edge.to] = v
+			if !result.discover(edge.to, depth+1, &clock, options) {
+				return result, nil
+			}
+			queue = append(queue, edge.to)
+		}
+		result.finish(v, &clock, options)
+	}
+	return result, nil
+}
+
+// Traverses the network depth first from start
+func DepthFirst(G *Core.Network, start uint32, options *TraversalOptions) (*TraversalResult, error) {
+	options, err := checkTraversal(G, start, options)
+	if err != nil {
+		return nil, err
+	}
+	result := newTraversalResult()
+	clock := 0
+	depthFirstFrom(G, start, options, result, &clock)
+	return result, nil
+}
+
+// Traverses the whole network depth first, starting a new tree at each undiscovered vertex in ascending order of id
+func DepthFirstForest(G *Core.Network, options *TraversalOptions) (*TraversalResult, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if options == nil {
+		options = NewTraversalOptions()
+	}
+	result := newTraversalResult()
+	clock := 0
+	for _, v := range G.Vertices(true) {
+		if _, seen := result.Discovered[v]; seen {
+			continue
+		}
+		if !depthFirstFrom(G, v, options, result, &clock) {
+			break
+		}
+	}
+	return result, nil
+}
+
+// Lazily yields vertices in depth-first pre-order or post-order
+type VertexIterator struct {
+	G         *Core.Network
+	direction TraversalDirection
+	postOrder bool
+	seen      map[uint32]bool
+	stack     []*traversalFrame
+	pending   []uint32 // roots not yet traversed
+}
+
+type traversalFrame struct {
+	vertex    uint32
+	neighbors []traversalEdge
+	pos       int
+}
+
+// Iterates the vertices reachable from start in depth-first pre-order (each vertex before its descendants)
+func PreOrderIterator(G *Core.Network, start uint32, direction TraversalDirection) *VertexIterator {
+	return newVertexIterator(G, []uint32{start}, direction, false)
+}
+
+// Iterates the vertices reachable from start in depth-first post-order (each vertex after its descendants)
+func PostOrderIterator(G *Core.Network, start uint32, direction TraversalDirection) *VertexIterator {
+	return newVertexIterator(G, []uint32{start}, direction, true)
+}
+
+func newVertexIterator(G *Core.Network, roots []uint32, direction TraversalDirection, postOrder bool) *VertexIterator {
+	it := new(VertexIterator)
+	it.G = G
+	it.direction = direction
+	it.postOrder = postOrder
+	it.seen = make(map[uint32]bool)
+	it.pending = make([]uint32, 0, len(roots))
+	for _, root := range roots {
+		if G.HasVertex(root) {
+			it.pending = append(it.pending, root)
+		}
+	}
+	return it
+}
+
+// Returns the next vertex, or false when the traversal is exhausted
+func (it *VertexIterator) Next() (uint32, bool) {
+	for {
+		if len(it.stack) == 0 {
+			for len(it.pending) > 0 && it.seen[it.pending[0]] {
+				it.pending = it.pending[1:]
+			}
+			if len(it.pending) == 0 {
+				return 0, false
+			}
+			root := it.pending[0]
+			it.pending = it.pending[1:]
+			it.seen[root] = true
+			it.stack = append(it.stack, &traversalFrame{vertex: root, neighbors: traversalNeighbors(it.G, root, it.direction)})
+			if !it.postOrder {
+				return root, true
+			}
+		}
+
+		top := it.stack[len(it.stack)-1]
+		if top.pos < len(top.neighbors) {
+			next := top.neighbors[top.pos].to
+			top.pos++
+			if !it.seen[next] {
+				it.seen[next] = true
+				it.stack = append(it.stack, &traversalFrame{vertex: next, neighbors: traversalNeighbors(it.G, next, it.direction)})
+				if !it.postOrder {
+					return next, true
+				}
+			}
+			continue
+		}
+
+		it.stack = it.stack[:len(it.stack)-1]
+		if it.postOrder {
+			return top.vertex, true
+		}
+	}
+}
+
+// Orders the vertices of a directed network so that every edge runs from an earlier vertex to a later one.
+// If the network has a cycle, a *CycleError holding one cycle is returned.
+func TopologicalSort(G *Core.Network) ([]uint32, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if !G.Directed() {
+		return nil, Core.NewNetworkArgumentError("Topological sorting requires a directed network")
+	}
+
+	const (
+		white = iota
+		gray
+		black
+	)
+	color := make(map[uint32]int, G.Order())
+	parent := make(map[uint32]uint32)
+	retVal := make([]uint32, 0, G.Order())
+
+	for _, root := range G.Vertices(true) {
+		if color[root] != white {
+			continue
+		}
+		color[root] = gray
+		stack := []*traversalFrame{{vertex: root, neighbors: traversalNeighbors(G, root, Outgoing)}}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.pos < len(top.neighbors) {
+				next := top.neighbors[top.pos].to
+				top.pos++
+				switch color[next] {
+				case white:
+					color[next] = gray
+					parent[next] = top.vertex
+					stack = append(stack, &traversalFrame{vertex: next, neighbors: traversalNeighbors(G, next, Outgoing)})
+				case gray:
+					// back edge: the cycle runs from next down the tree to top and back
+					cycle := []uint32{top.vertex}
+					for v := top.vertex; v != next; {
+						v = parent[v]
+						cycle = append(cycle, v)
+					}
+					for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+						cycle[i], cycle[j] = cycle[j], cycle[i]
+					}
+					return nil, NewCycleError("Network is not acyclic", cycle)
+				}
+				continue
+			}
+			color[top.vertex] = black
+			retVal = append(retVal, top.vertex)
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	// reverse post-order
+	for i, j := 0, len(retVal)-1; i < j; i, j = i+1, j-1 {
+		retVal[i], retVal[j] = retVal[j], retVal[i]
+	}
+	return retVal, nil
+}
+
+func checkTraversal(G *Core.Network, start uint32, options *TraversalOptions) (*TraversalOptions, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if !G.HasVertex(start) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Starting vertex %d is not in the network", start))
+	}
+	if options == nil {
+		options = NewTraversalOptions()
+	}
+	return options, nil
+}
+
+func newTraversalResult() *TraversalResult {
+	result := new(TraversalResult)
+	result.Order = make([]uint32, 0)
+	result.Discovered = make(map[uint32]int)
+	result.Finished = make(map[uint32]int)
+	result.Parent = make(map[uint32]uint32)
+	result.Depth = make(map[uint32]int)
+	return result
+}
+
+// record a discovery and return false if the visitor asks to stop
+func (result *TraversalResult) discover(vertex uint32, depth int, clock *int, options *TraversalOptions) bool {
+	result.Order = append(result.Order, vertex)
+	result.Discovered[vertex] = *clock
+	result.Depth[vertex] = depth
+	*clock++
+	if options.Visitor != nil && options.Visitor.DiscoverVertex != nil && !options.Visitor.DiscoverVertex(vertex, depth) {
+		result.Stopped = true
+		return false
+	}
+	return true
+}
+
+func (result *TraversalResult) finish(vertex uint32, clock *int, options *TraversalOptions) {
+	result.Finished[vertex] = *clock
+	*clock++
+	if options.Visitor != nil && options.Visitor.FinishVertex != nil {
+		options.Visitor.FinishVertex(vertex)
+	}
+}
+
+// iterative depth-first search from start into result; returns false if the visitor stopped the traversal
+func depthFirstFrom(G *Core.Network, start uint32, options *TraversalOptions, result *TraversalResult, clock *int) bool {
+	if !result.discover(start, 0, clock, options) {
+		return false
+	}
+	stack := []*traversalFrame{{vertex: start, neighbors: traversalNeighbors(G, start, options.Direction)}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		if top.pos < len(top.neighbors) {
+			edge := top.neighbors[top.pos]
+			top.pos++
+			if options.Visitor != nil && options.Visitor.ExamineEdge != nil {
+				options.Visitor.ExamineEdge(top.vertex, edge.to, edge.weight)
+			}
+			depth := result.Depth[top.vertex] + 1
+			if _, seen := result.Discovered[edge.to]; seen || (options.MaxDepth >= 0 && depth > options.MaxDepth) {
+				continue
+			}
+			result.Parent[edge.to] = top.vertex
+			if !result.discover(edge.to, depth, clock, options) {
+				return false
+			}
+			stack = append(stack, &traversalFrame{vertex: edge.to, neighbors: traversalNeighbors(G, edge.to, options.Direction)})
+			continue
+		}
+		result.finish(top.vertex, clock, options)
+		stack = stack[:len(stack)-1]
+	}
+	return true
+}
+
+// the neighbors reached from vertex in the given direction, sorted by id
+func traversalNeighbors(G *Core.Network, vertex uint32, direction TraversalDirection) []traversalEdge {
+	var neighbors map[uint32]float32
+	if !G.Directed() || direction == Outgoing {
+		neighbors = G.GetNeighbors(vertex)
+	} else if direction == Incoming {
+		neighbors = G.GetSources(vertex)
+	} else {
+		neighbors = G.GetNeighbors(vertex)
+		for from, wt := range G.GetSources(vertex) {
+			if _, ok := neighbors[from]; !ok {
+				neighbors[from] = wt
+			}
+		}
+	}
+
+	retVal := make([]traversalEdge, 0, len(neighbors))
+	for to, wt := range neighbors {
+		retVal = append(retVal, traversalEdge{to: to, weight: wt})
+	}
+	sort.Slice(retVal, func(i, j int) bool { return retVal[i].to < retVal[j].to })
+	return retVal
+}
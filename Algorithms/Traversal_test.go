@@ -0,0 +1,174 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Algorithms
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"testing"
+)
+
+// 1 -> 2 -> 4, 1 -> 3 -> 4, 4 -> 5
+func makeDag() *Core.Network {
+	G := Core.NewNetwork(true)
+	_ = G.AddEdge(1, 2, 1.0)
+	_ = G.AddEdge(1, 3, 1.0)
+	_ = G.AddEdge(2, 4, 1.0)
+	_ = G.AddEdge(3, 4, 1.0)
+	_ = G.AddEdge(4, 5, 1.0)
+	return G
+}
+
+func TestBreadthFirst(t *testing.T) {
+	G := makeDag()
+	examined := 0
+	options := NewTraversalOptions()
+	options.Visitor = &Visitor{ExamineEdge: func(from uint32, to uint32, weight float32) { examined++ }}
+	result, err := BreadthFirst(G, 1, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{1, 2, 3, 4, 5}
+	for i, v := range expected {
+		if result.Order[i] != v {
+			t.Fatalf("Expected breadth-first order %v, found %v", expected, result.Order)
+		}
+	}
+	if result.Depth[4] != 2 || result.Parent[4] != 2 {
+		t.Errorf("Expected vertex 4 at depth 2 with parent 2, found depth %d parent %d", result.Depth[4], result.Parent[4])
+	}
+	if examined != 5 {
+		t.Errorf("Expected 5 edges examined, found %d", examined)
+	}
+
+	options.MaxDepth = 1
+	result, _ = BreadthFirst(G, 1, options)
+	if len(result.Order) != 3 {
+		t.Errorf("Expected 3 vertices within depth 1, found %v", result.Order)
+	}
+
+	options = NewTraversalOptions()
+	options.Direction = Incoming
+	result, _ = BreadthFirst(G, 4, options)
+	if len(result.Order) != 4 {
+		t.Errorf("Expected 4 vertices reaching vertex 4, found %v", result.Order)
+	}
+
+	if _, err = BreadthFirst(G, 9, nil); err == nil {
+		t.Errorf("Expected an error for a missing start vertex")
+	}
+}
+
+func TestDepthFirst(t *testing.T) {
+	G := makeDag()
+	finished := make([]uint32, 0)
+	options := NewTraversalOptions()
+	options.Visitor = &Visitor{FinishVertex: func(vertex uint32) { finished = append(finished, vertex) }}
+	result, err := DepthFirst(G, 1, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{1, 2, 4, 5, 3}
+	for i, v := range expected {
+		if result.Order[i] != v {
+			t.Fatalf("Expected depth-first order %v, found %v", expected, result.Order)
+		}
+	}
+	// parenthesis theorem: descendants nest within ancestors
+	if !(result.Discovered[1] < result.Discovered[4] && result.Finished[4] < result.Finished[1]) {
+		t.Errorf("Discovery and finishing times of 1 and 4 do not nest")
+	}
+	if finished[0] != 5 || finished[len(finished)-1] != 1 {
+		t.Errorf("Expected vertex 5 to finish first and 1 last, found %v", finished)
+	}
+
+	stop := NewTraversalOptions()
+	stop.Visitor = &Visitor{DiscoverVertex: func(vertex uint32, depth int) bool { return vertex != 4 }}
+	result, _ = DepthFirst(G, 1, stop)
+	if !result.Stopped || len(result.Order) != 3 {
+		t.Errorf("Expected traversal to stop on discovering 4, found %v", result.Order)
+	}
+
+	G.AddVertex(7)
+	result, _ = DepthFirstForest(G, nil)
+	if len(result.Order) != 6 || len(result.Finished) != 6 {
+		t.Errorf("Expected the forest to cover 6 vertices, found %v", result.Order)
+	}
+}
+
+func TestOrderIterators(t *testing.T) {
+	G := makeDag()
+	pre := make([]uint32, 0)
+	it := PreOrderIterator(G, 1, Outgoing)
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		pre = append(pre, v)
+	}
+	post := make([]uint32, 0)
+	it = PostOrderIterator(G, 1, Outgoing)
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		post = append(post, v)
+	}
+	expectedPre := []uint32{1, 2, 4, 5, 3}
+	expectedPost := []uint32{5, 4, 2, 3, 1}
+	for i := range expectedPre {
+		if pre[i] != expectedPre[i] || post[i] != expectedPost[i] {
+			t.Fatalf("Expected pre-order %v and post-order %v, found %v and %v", expectedPre, expectedPost, pre, post)
+		}
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	G := makeDag()
+	order, err := TopologicalSort(G)
+	if err != nil {
+		t.Fatal(err)
+	}
+	position := make(map[uint32]int)
+	for i, v := range order {
+		position[v] = i
+	}
+	for _, v := range G.Vertices(false) {
+		for to := range G.GetNeighbors(v) {
+			if position[v] >= position[to] {
+				t.Errorf("Edge %d -> %d violates topological order %v", v, to, order)
+			}
+		}
+	}
+
+	_ = G.AddEdge(5, 2, 1.0)
+	_, err = TopologicalSort(G)
+	cycleErr, ok := err.(*CycleError)
+	if !ok {
+		t.Fatalf("Expected a CycleError, found %v", err)
+	}
+	if len(cycleErr.Cycle) != 3 {
+		t.Errorf("Expected cycle 2 -> 4 -> 5, found %v", cycleErr.Cycle)
+	}
+	for i, v := range cycleErr.Cycle {
+		if !G.HasEdge(v, cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]) {
+			t.Errorf("Reported cycle %v is not a cycle", cycleErr.Cycle)
+		}
+	}
+
+	if _, err = TopologicalSort(Core.NewNetwork(false)); err == nil {
+		t.Errorf("Expected an error sorting an undirected network")
+	}
+}
@@ -22,6 +22,7 @@
 package Algorithms
 
 import (
+	"context"
 	"github.com/smohr1824/DataStructures"
 	"github.com/smohr1824/Networks/Core"
 )
@@ -34,85 +35,176 @@ const (
 type coloring struct {
 	vertex uint32
 	color  uint8
+	parent uint32	// the vertex whose neighbors proposed this coloring; a root is its own parent
+}
+
+// Result of bipartite discovery.  If the network is not bipartite, OddCycle holds an odd cycle proving it, first vertex not repeated,
+// and R and B are nil.
+type BipartiteResult struct {
+	IsBipartite bool
+	R []uint32
+	B []uint32
+	OddCycle []uint32
 }
 
 // entry point for concurrent bipartite discovery
 // The network will only be read from, not written to
 // routineCount is the number of concurrent goroutines to use and should be approximately equal to the average degree of the network
 func ConcurrentBipartite(G *Core.Network, routineCount int) (bool, []uint32, []uint32) {
+	result, err := ConcurrentBipartiteContext(context.Background(), G, routineCount, nil)
+	if err != nil || !result.IsBipartite {
+		return false, nil, nil
+	}
+	return true, result.R, result.B
+}
+
+// As ConcurrentBipartite, but returns an odd cycle when the network is not bipartite and stops with the context's error if it is
+// cancelled or times out.  Every connected component is colored, each from its lowest vertex id, and progress, if not nil, is reported
+// as each component is completed.
+func ConcurrentBipartiteContext(ctx context.Context, G *Core.Network, routineCount int, progress ProgressFunc) (*BipartiteResult, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	if routineCount < 1 {
+		routineCount = 1
+	}
+
 	maxSize := G.Order()
 	R := make([]uint32, 0, maxSize)
 	B := make([]uint32, 0, maxSize)
 	colorings := make(map[uint32]uint8, maxSize)
+	parents := make(map[uint32]uint32, maxSize)
 
 	// worklist is the queue of vertices on the frontier (BFS)
 	worklist := DataStructures.NewQueue()
-	isBipartite := true
-
-	start, err := G.StartingVertex(true)
-	if err != nil {
-		return false, nil, nil
-	}
-
 
-	// color the starting vertex and load it into the queue
-	colorings[start] = red
-	R = append(R, start)
-	startColor := coloring{start, red}
-	initial := make([]coloring, 1)
-	initial[0] = startColor
-	worklist.Push(initial)
+	// roots of the components, taken in order as each component is exhausted
+	vertices := G.Vertices(true)
+	nextRoot := 0
 	nextChannel := 0
 	order := G.Order()
 
+	// pending counts vertices handed to goroutines whose neighbors have not yet come back
+	// outgoing holds an assignment waiting for its goroutine's channel to accept it
+	pending := 0
+	var outgoing []coloring
+	reporter := newProgressReporter("ConcurrentBipartiteContext", progress)
+	components := 0
+
 	// coloringChannel receives arrays of colorings from goroutines
 	// assignments is an array of the channels for sending a single assignment to a goroutine
+	// done is closed on return so goroutines blocked sending colorings can exit
 	coloringChannel := make(chan []coloring, routineCount*5)
+	done := make(chan struct{})
 
 	assignments := prepareWorkChannels(routineCount)
 	defer func() {
-		close(coloringChannel)
+		close(done)
 	}()
 
-	isBipartite = true
-
 	// start the goroutines
 	for i := 0; i < routineCount; i++ {
-		go serviceAssignments(G, assignments[i], coloringChannel)
+		go serviceAssignments(G, assignments[i], coloringChannel, done)
 	}
 
-	// start processing
-	for len(colorings) < order {
+	// start processing; every vertex may be colored while neighbor colorings are still queued or in flight, and those must still be checked
+	// for conflicts, so the loop runs until no work remains
+	for {
+
+		// when a component is exhausted, color the lowest uncolored vertex and start on its component, or stop if none remains
+		if outgoing == nil && worklist.Length() == 0 && pending == 0 {
+			if len(colorings) == order {
+				break
+			}
+			if components > 0 {
+				reporter.emit(components, -1)
+			}
+			components++
+			for _, ok := colorings[vertices[nextRoot]]; ok; _, ok = colorings[vertices[nextRoot]] {
+				nextRoot++
+			}
+			root := vertices[nextRoot]
+			colorings[root] = red
+			parents[root] = root
+			R = append(R, root)
+			worklist.Push([]coloring{{root, red, root}})
+		}
 
 		// assign any available work items
-		qlen := worklist.Length()
-		if qlen > 0 {
-			assignment := worklist.Pop()
-			assignments[nextChannel] <- assignment.([]coloring)
+		if outgoing == nil && worklist.Length() > 0 {
+			outgoing = worklist.Pop().([]coloring)
+		}
+		var send chan []coloring
+		if outgoing != nil {
+			send = assignments[nextChannel]
+		}
+
+		select {
+		case send <- outgoing:
+			pending += len(outgoing)
+			outgoing = nil
 			nextChannel++
 			if nextChannel >= routineCount {
 				nextChannel = 0
 			}
-		}
 
-		select {
 		// process colorings from goroutines
 		case coloringMsg := <-coloringChannel:
-			trigger := processColorings(coloringMsg, colorings, &R, &B, worklist)
-			if trigger {
+			pending--
+			conflict := processColorings(coloringMsg, colorings, parents, &R, &B, worklist)
+			if conflict != nil {
 				closeChannels(assignments)
-				return false, nil, nil
+				return &BipartiteResult{IsBipartite: false, OddCycle: oddCycle(parents, conflict.parent, conflict.vertex)}, nil
 			}
+
+		case <-ctx.Done():
+			closeChannels(assignments)
+			return nil, ctx.Err()
 		}
 	}
 
 	// close the work assignment channels to cause the goroutines to terminate
 	closeChannels(assignments)
-	if isBipartite {
-		return isBipartite, R, B
-	} else {
-		return false, nil, nil
+	reporter.emit(components, -1)
+	return &BipartiteResult{IsBipartite: true, R: R, B: B}, nil
+}
+
+// u and v are adjacent and have the same color, so the tree paths from each to their common ancestor have the same parity and,
+// closed by the edge between them, form an odd cycle
+func oddCycle(parents map[uint32]uint32, u uint32, v uint32) []uint32 {
+	depth := func(x uint32) int {
+		d := 0
+		for parents[x] != x {
+			x = parents[x]
+			d++
+		}
+		return d
+	}
+
+	fromU := []uint32{u}
+	fromV := []uint32{v}
+	du, dv := depth(u), depth(v)
+	for ; du > dv; du-- {
+		u = parents[u]
+		fromU = append(fromU, u)
+	}
+	for ; dv > du; dv-- {
+		v = parents[v]
+		fromV = append(fromV, v)
+	}
+	for u != v {
+		u = parents[u]
+		v = parents[v]
+		fromU = append(fromU, u)
+		fromV = append(fromV, v)
 	}
+
+	// u ... ancestor ... v, then back to u along the edge
+	retVal := fromU
+	for i := len(fromV) - 2; i >= 0; i-- {
+		retVal = append(retVal, fromV[i])
+	}
+	return retVal
 }
 
 // create an array of channels for passing work assignments to goroutines
@@ -132,9 +224,7 @@ func closeChannels(channels []chan []coloring) {
 }
 
 // goroutine enumerates the neighbors, assigns a color, and sends it back to main for review
-func serviceAssignments(G *Core.Network, localAssignmentChannel <-chan []coloring, coloringChannel chan<- []coloring) {
-	// depending on the timing, a goroutine may send on the main coloring channel after it has been closed, hence this call
-	defer func() { recover() } ()
+func serviceAssignments(G *Core.Network, localAssignmentChannel <-chan []coloring, coloringChannel chan<- []coloring, done <-chan struct{}) {
 	assignments, ok := <-localAssignmentChannel
 	for ok {
 		for _, assigned := range assignments {
@@ -162,12 +252,17 @@ func serviceAssignments(G *Core.Network, localAssignmentChannel <-chan []colorin
 			newassignments := make([]coloring, len(neighbors))
 			i := 0
 			for key := range neighbors {
-				colorassignment := coloring{key, tocolor}
+				colorassignment := coloring{key, tocolor, parentVertex}
 				newassignments[i] = colorassignment
 				i++
 			}
 
-			coloringChannel <- newassignments
+			// main may have stopped listening after a conflict or cancellation
+			select {
+			case coloringChannel <- newassignments:
+			case <-done:
+				return
+			}
 		}
 		assignments, ok = <-localAssignmentChannel
 	}
@@ -176,28 +271,30 @@ func serviceAssignments(G *Core.Network, localAssignmentChannel <-chan []colorin
 // go through an array of proposed colorings from a goroutine
 // if not previously seen, add to the map and add it to the work queue
 // if seen, make sure there is no conflict, but do not process further
-// If a conflict is seen, the graph is not bipartite.
-func processColorings(assignedColors []coloring, masterColors map[uint32]uint8, R *[]uint32, B *[]uint32, queue *DataStructures.Queue) bool {
+// If a conflict is seen, the graph is not bipartite, and the conflicting coloring is returned.
+func processColorings(assignedColors []coloring, masterColors map[uint32]uint8, parents map[uint32]uint32, R *[]uint32, B *[]uint32, queue *DataStructures.Queue) *coloring {
 	filteredColorings := make([]coloring, 0, len(assignedColors))
-	for _, colored := range assignedColors {
+	for i, colored := range assignedColors {
 		color, ok := masterColors[colored.vertex]
 		if !ok {
 			masterColors[colored.vertex] = colored.color
+			parents[colored.vertex] = colored.parent
 			if colored.color == red {
 				*R = append(*R, colored.vertex)
 			} else {
 				*B = append(*B, colored.vertex)
 			}
-			//queue.Push(colored)
 			filteredColorings = append(filteredColorings, colored)
 		} else {
 			// found, check for conflict, abort all if there is a conflict
 			if color != colored.color {
-				return true
+				return &assignedColors[i]
 			}
 		}
 
 	}
-	queue.Push(filteredColorings)
-	return false
+	if len(filteredColorings) > 0 {
+		queue.Push(filteredColorings)
+	}
+	return nil
 }
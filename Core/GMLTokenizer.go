@@ -70,6 +70,17 @@ func (tokenizer *GMLTokenizer) ReadNextToken (reader *bufio.Reader) string {
 
 func (tokenizer *GMLTokenizer) ReadListRecord (reader *bufio.Reader) map[string] string {
 	props := make(map[string]string)
+	keys, values := tokenizer.ReadOrderedListRecord(reader)
+	for i, key := range keys {
+		props[key] = values[i]
+	}
+	return props
+}
+
+// reads a list record as parallel slices of keys and values in the order they appear in the document
+func (tokenizer *GMLTokenizer) ReadOrderedListRecord (reader *bufio.Reader) ([]string, []string) {
+	keys := make([]string, 0)
+	values := make([]string, 0)
 
 	tokenizer.EatWhitespace(reader)
 
@@ -100,12 +111,13 @@ func (tokenizer *GMLTokenizer) ReadListRecord (reader *bufio.Reader) map[string]
 				ch, _, err = reader.ReadRune()
 			}
 		}
-		(props)[key] = value
+		keys = append(keys, key)
+		values = append(values, value)
 		tokenizer.EatWhitespace(reader)
 		key = tokenizer.ReadNextToken(reader)
 	}
 
-	return props
+	return keys, values
 }
 
 func (tokenizer *GMLTokenizer) ReadNextValue(reader *bufio.Reader) string {
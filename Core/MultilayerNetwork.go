@@ -552,8 +552,8 @@ func (p *MultilayerNetwork) AddVertex(vertex NodeLayerTuple) (bool, error) {
 			layerList[0] = layer
 			p.nodeIdsAndLayers[rVertex.NodeId] = layerList
 		} else {
-			if p.layerLoc(layers, *layer) != -1 {
-				layers = append(layers, layer)
+			if p.layerLoc(layers, *layer) == -1 {
+				p.nodeIdsAndLayers[rVertex.NodeId] = append(layers, layer)
 			}
 		}
 		return true, nil
@@ -584,6 +584,8 @@ func (p *MultilayerNetwork) RemoveVertex(vertex NodeLayerTuple) (bool, error) {
 		}
 		if len(layers) == 0 {
 			delete(p.nodeIdsAndLayers, rVertex.NodeId)
+		} else {
+			p.nodeIdsAndLayers[rVertex.NodeId] = layers
 		}
 		return true, nil
 	} else {
@@ -613,13 +615,13 @@ func (p *MultilayerNetwork) AddEdge(from NodeLayerTuple, to NodeLayerTuple, wt f
 	_, ok := p.nodeIdsAndLayers[from.NodeId]
 	if !ok {
 		// add an entry for the node
-		layers := make([]*elementaryLayer, 1)
+		layers := make([]*elementaryLayer, 0)
 		p.nodeIdsAndLayers[from.NodeId] = layers
 	}
 
 	_, ok = p.nodeIdsAndLayers[to.NodeId]
 	if !ok {
-		layers := make([]*elementaryLayer, 1)
+		layers := make([]*elementaryLayer, 0)
 		p.nodeIdsAndLayers[to.NodeId] = layers
 	}
 
@@ -630,7 +632,7 @@ func (p *MultilayerNetwork) AddEdge(from NodeLayerTuple, to NodeLayerTuple, wt f
 			if ok {
 				p.nodeIdsAndLayers[rFrom.NodeId] = append(p.nodeIdsAndLayers[rFrom.NodeId], fromLayer)
 			} else {
-				layers := make([]*elementaryLayer, 1)
+				layers := make([]*elementaryLayer, 0)
 				layers = append(layers, fromLayer)
 				p.nodeIdsAndLayers[rFrom.NodeId] = layers
 			}
@@ -641,7 +643,7 @@ func (p *MultilayerNetwork) AddEdge(from NodeLayerTuple, to NodeLayerTuple, wt f
 			if ok {
 				p.nodeIdsAndLayers[rTo.NodeId] = append(p.nodeIdsAndLayers[rTo.NodeId], fromLayer)
 			} else {
-				layers := make([]*elementaryLayer, 1)
+				layers := make([]*elementaryLayer, 0)
 				layers = append(layers, fromLayer)
 				p.nodeIdsAndLayers[rTo.NodeId] = layers
 			}
@@ -656,7 +658,7 @@ func (p *MultilayerNetwork) AddEdge(from NodeLayerTuple, to NodeLayerTuple, wt f
 
 		if !toLayer.HasVertex(rTo.NodeId) {
 			toLayer.AddVertex(rTo.NodeId)
-			p.nodeIdsAndLayers[rTo.NodeId] = append(p.nodeIdsAndLayers[rTo.NodeId], fromLayer)
+			p.nodeIdsAndLayers[rTo.NodeId] = append(p.nodeIdsAndLayers[rTo.NodeId], toLayer)
 		}
 		// vertices definitely exist, add the edge
 		fromLayer.AddEdge(rFrom, rTo, wt)
@@ -787,6 +789,7 @@ func (p *MultilayerNetwork) removeElementaryLayerFromMultilayerNetwork(resolved
 					layers[i] = layers[len(layers)-1]
 					layers[len(layers)-1] = nil     // turn last into nil
 					layers = layers[:len(layers)-1] // truncate
+					p.nodeIdsAndLayers[vertex] = layers
 				}
 			}
 		}
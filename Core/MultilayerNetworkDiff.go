@@ -0,0 +1,410 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Structural differences between two multilayer networks sharing the same aspects and indices
+
+package Core
+
+import (
+	"bufio"
+	"encoding/json"
+	. "fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// an explicit interlayer edge that was added, removed, or had its weight changed
+type InterlayerEdgeChange struct {
+	From      NodeLayerTuple `json:"from"`
+	To        NodeLayerTuple `json:"to"`
+	OldWeight float32        `json:"oldWeight"`
+	NewWeight float32        `json:"newWeight"`
+}
+
+// change set taking one multilayer network to another
+// Layers holds the intralayer changes of every elementary layer present in both networks that changed, keyed by aspect coordinates.
+// AddedLayers holds the contents of each new layer expressed as a diff against an empty network.
+type MultilayerNetworkDiff struct {
+	Directed                 bool                    `json:"directed"`
+	AddedLayers              map[string]*NetworkDiff `json:"addedLayers"`
+	RemovedLayers            []string                `json:"removedLayers"`
+	Layers                   map[string]*NetworkDiff `json:"layers"`
+	AddedInterlayerEdges     []InterlayerEdgeChange  `json:"addedInterlayerEdges"`
+	RemovedInterlayerEdges   []InterlayerEdgeChange  `json:"removedInterlayerEdges"`
+	ChangedInterlayerWeights []InterlayerEdgeChange  `json:"changedInterlayerWeights"`
+}
+
+type interlayerEdgeKey struct {
+	from NodeLayerTuple
+	to   NodeLayerTuple
+}
+
+// Computes the change set from older to newer.  The networks must have the same aspects, indices, and value of directed.
+func DiffMultilayer(older *MultilayerNetwork, newer *MultilayerNetwork, tolerance float32) (*MultilayerNetworkDiff, error) {
+	if older == nil || newer == nil {
+		return nil, NewNetworkArgumentNullError("Both multilayer networks must be non-null")
+	}
+
+	if older.directed != newer.directed {
+		return nil, NewNetworkArgumentError("Both multilayer networks must have the same value of directed")
+	}
+
+	if !reflect.DeepEqual(older.aspects, newer.aspects) || !reflect.DeepEqual(older.indices, newer.indices) {
+		return nil, NewNetworkArgumentError("Both multilayer networks must have the same aspects and indices")
+	}
+
+	retVal := newMultilayerNetworkDiff(older.directed)
+
+	for coords, layer := range newer.elementaryLayers {
+		oldLayer, ok := older.elementaryLayers[coords]
+		if !ok {
+			added, err := Diff(NewNetwork(newer.directed), layer.g, tolerance)
+			if err != nil {
+				return nil, err
+			}
+			retVal.AddedLayers[newer.UnaliasCoordinates(coords)] = added
+			continue
+		}
+
+		changed, err := Diff(oldLayer.g, layer.g, tolerance)
+		if err != nil {
+			return nil, err
+		}
+		if !changed.IsEmpty() {
+			retVal.Layers[newer.UnaliasCoordinates(coords)] = changed
+		}
+	}
+
+	for coords := range older.elementaryLayers {
+		if !newer.elementaryLayerExists(coords) {
+			retVal.RemovedLayers = append(retVal.RemovedLayers, older.UnaliasCoordinates(coords))
+		}
+	}
+
+	oldEdges := older.interlayerEdges()
+	newEdges := newer.interlayerEdges()
+	for key, wt := range oldEdges {
+		newWt, ok := newEdges[key]
+		if !ok {
+			retVal.RemovedInterlayerEdges = append(retVal.RemovedInterlayerEdges, InterlayerEdgeChange{From: key.from, To: key.to, OldWeight: wt})
+		} else if math.Abs(float64(newWt-wt)) > float64(tolerance) {
+			retVal.ChangedInterlayerWeights = append(retVal.ChangedInterlayerWeights, InterlayerEdgeChange{From: key.from, To: key.to, OldWeight: wt, NewWeight: newWt})
+		}
+	}
+
+	for key, wt := range newEdges {
+		_, ok := oldEdges[key]
+		if !ok {
+			retVal.AddedInterlayerEdges = append(retVal.AddedInterlayerEdges, InterlayerEdgeChange{From: key.from, To: key.to, NewWeight: wt})
+		}
+	}
+
+	retVal.sort()
+	return retVal, nil
+}
+
+func newMultilayerNetworkDiff(directed bool) *MultilayerNetworkDiff {
+	retVal := new(MultilayerNetworkDiff)
+	retVal.Directed = directed
+	retVal.AddedLayers = make(map[string]*NetworkDiff)
+	retVal.RemovedLayers = make([]string, 0)
+	retVal.Layers = make(map[string]*NetworkDiff)
+	retVal.AddedInterlayerEdges = make([]InterlayerEdgeChange, 0)
+	retVal.RemovedInterlayerEdges = make([]InterlayerEdgeChange, 0)
+	retVal.ChangedInterlayerWeights = make([]InterlayerEdgeChange, 0)
+	return retVal
+}
+
+func (diff *MultilayerNetworkDiff) IsEmpty() bool {
+	return len(diff.AddedLayers) == 0 && len(diff.RemovedLayers) == 0 && len(diff.Layers) == 0 &&
+		len(diff.AddedInterlayerEdges) == 0 && len(diff.RemovedInterlayerEdges) == 0 && len(diff.ChangedInterlayerWeights) == 0
+}
+
+// Applies the change set to M, which should be the older network the diff was computed against.  Apply is all or nothing: every
+// layer and interlayer change is checked against M before anything is modified, and M is left unchanged if any check fails.
+func (diff *MultilayerNetworkDiff) Apply(M *MultilayerNetwork) error {
+	if M == nil {
+		return NewNetworkArgumentNullError("Multilayer network must be non-null")
+	}
+
+	if M.directed != diff.Directed {
+		return NewNetworkArgumentError("The multilayer network and the diff must have the same value of directed")
+	}
+
+	for coords := range diff.AddedLayers {
+		if M.HasElementaryLayer(coords) {
+			return NewNetworkArgumentError(Sprintf("Layer %s to be added already exists", coords))
+		}
+	}
+	for _, coords := range diff.RemovedLayers {
+		if !M.HasElementaryLayer(coords) {
+			return NewNetworkArgumentError(Sprintf("Layer %s to be removed does not exist", coords))
+		}
+	}
+	for _, layerDiff := range diff.AddedLayers {
+		err := layerDiff.validate(NewNetwork(M.directed))
+		if err != nil {
+			return err
+		}
+	}
+	for coords, layerDiff := range diff.Layers {
+		G, err := M.layerNetwork(coords)
+		if err != nil {
+			return err
+		}
+		err = layerDiff.validate(G)
+		if err != nil {
+			return err
+		}
+	}
+	for _, edge := range diff.AddedInterlayerEdges {
+		if edge.From.NodeId == edge.To.NodeId {
+			return NewNetworkArgumentError(Sprintf("Interlayer edge %s, %s to be added joins a vertex to itself", edge.From.ToString(), edge.To.ToString()))
+		}
+		if !diff.layerAfterApply(M, edge.From.Coordinates) || !diff.layerAfterApply(M, edge.To.Coordinates) {
+			return NewNetworkArgumentError(Sprintf("The layer of interlayer edge %s, %s to be added does not exist", edge.From.ToString(), edge.To.ToString()))
+		}
+		if M.HasEdge(edge.From, edge.To) {
+			return NewNetworkArgumentError(Sprintf("Interlayer edge %s, %s to be added already exists", edge.From.ToString(), edge.To.ToString()))
+		}
+	}
+	for _, edge := range diff.RemovedInterlayerEdges {
+		if !M.HasEdge(edge.From, edge.To) {
+			return NewNetworkArgumentError(Sprintf("Interlayer edge %s, %s to be removed does not exist", edge.From.ToString(), edge.To.ToString()))
+		}
+	}
+	for _, edge := range diff.ChangedInterlayerWeights {
+		if !M.HasEdge(edge.From, edge.To) {
+			return NewNetworkArgumentError(Sprintf("Interlayer edge %s, %s to be changed does not exist", edge.From.ToString(), edge.To.ToString()))
+		}
+	}
+
+	for _, coords := range diff.sortedLayerKeys(diff.AddedLayers) {
+		_, err := M.AddElementaryLayer(coords, NewNetwork(M.directed))
+		if err != nil {
+			return err
+		}
+		err = M.applyLayerDiff(coords, diff.AddedLayers[coords])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, coords := range diff.sortedLayerKeys(diff.Layers) {
+		err := M.applyLayerDiff(coords, diff.Layers[coords])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range diff.RemovedInterlayerEdges {
+		_, err := M.RemoveEdge(edge.From, edge.To)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range diff.ChangedInterlayerWeights {
+		_, err := M.RemoveEdge(edge.From, edge.To)
+		if err != nil {
+			return err
+		}
+		_, err = M.AddEdge(edge.From, edge.To, edge.NewWeight)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range diff.AddedInterlayerEdges {
+		_, err := M.AddEdge(edge.From, edge.To, edge.NewWeight)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, coords := range diff.RemovedLayers {
+		_, err := M.RemoveElementaryLayer(coords)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Writes the change set as text.  Layer changes are introduced by a layer line giving the aspect coordinates and
+// are otherwise written as by NetworkDiff.List.  Interlayer edges give each endpoint as id:coordinates.
+func (diff *MultilayerNetworkDiff) List(writer *bufio.Writer, delimiter string) error {
+	var err error
+	if diff.Directed {
+		_, err = Fprintln(writer, "directed 1")
+	} else {
+		_, err = Fprintln(writer, "directed 0")
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, coords := range diff.sortedLayerKeys(diff.AddedLayers) {
+		_, _ = Fprintln(writer, "+layer"+delimiter+coords)
+		diff.AddedLayers[coords].listChanges(writer, delimiter, "\t")
+	}
+	for _, coords := range diff.RemovedLayers {
+		_, _ = Fprintln(writer, "-layer"+delimiter+coords)
+	}
+	for _, coords := range diff.sortedLayerKeys(diff.Layers) {
+		_, _ = Fprintln(writer, "layer"+delimiter+coords)
+		diff.Layers[coords].listChanges(writer, delimiter, "\t")
+	}
+	for _, edge := range diff.AddedInterlayerEdges {
+		_, _ = Fprintln(writer, Sprintf("+interlayer%s%s%s%s%s%s", delimiter, edge.From.ToString(), delimiter, edge.To.ToString(), delimiter, formatWeight(edge.NewWeight)))
+	}
+	for _, edge := range diff.RemovedInterlayerEdges {
+		_, _ = Fprintln(writer, Sprintf("-interlayer%s%s%s%s%s%s", delimiter, edge.From.ToString(), delimiter, edge.To.ToString(), delimiter, formatWeight(edge.OldWeight)))
+	}
+	for _, edge := range diff.ChangedInterlayerWeights {
+		_, _ = Fprintln(writer, Sprintf("~interlayer%s%s%s%s%s%s%s%s", delimiter, edge.From.ToString(), delimiter, edge.To.ToString(), delimiter, formatWeight(edge.OldWeight), delimiter, formatWeight(edge.NewWeight)))
+	}
+	return writer.Flush()
+}
+
+func (diff *MultilayerNetworkDiff) ListJSON(writer *bufio.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(diff)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func ReadMultilayerNetworkDiffJSON(reader io.Reader) (*MultilayerNetworkDiff, error) {
+	retVal := newMultilayerNetworkDiff(false)
+	err := json.NewDecoder(reader).Decode(retVal)
+	if err != nil {
+		return nil, err
+	}
+	return retVal, nil
+}
+
+func (diff *MultilayerNetworkDiff) sortedLayerKeys(layers map[string]*NetworkDiff) []string {
+	keys := make([]string, 0, len(layers))
+	for coords := range layers {
+		keys = append(keys, coords)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (diff *MultilayerNetworkDiff) sort() {
+	sort.Strings(diff.RemovedLayers)
+	sortInterlayerEdgeChanges(diff.AddedInterlayerEdges)
+	sortInterlayerEdgeChanges(diff.RemovedInterlayerEdges)
+	sortInterlayerEdgeChanges(diff.ChangedInterlayerWeights)
+}
+
+func sortInterlayerEdgeChanges(edges []InterlayerEdgeChange) {
+	sort.Slice(edges, func(i, j int) bool {
+		a := edges[i].From.ToString() + "|" + edges[i].To.ToString()
+		b := edges[j].From.ToString() + "|" + edges[j].To.ToString()
+		return a < b
+	})
+}
+
+// whether the layer exists in M or is one of the layers the diff adds
+func (diff *MultilayerNetworkDiff) layerAfterApply(M *MultilayerNetwork, coords string) bool {
+	if M.HasElementaryLayer(coords) {
+		return true
+	}
+	_, ok := diff.AddedLayers[coords]
+	return ok
+}
+
+// all explicit interlayer edges keyed by their unaliased endpoints
+func (p *MultilayerNetwork) interlayerEdges() map[interlayerEdgeKey]float32 {
+	retVal := make(map[interlayerEdgeKey]float32)
+	for coords, layer := range p.elementaryLayers {
+		fromCoords := p.UnaliasCoordinates(coords)
+		for from, targets := range layer.edgeList {
+			for to, wt := range targets {
+				key := interlayerEdgeKey{from: NodeLayerTuple{NodeId: from, Coordinates: fromCoords}, to: NodeLayerTuple{NodeId: to.NodeId, Coordinates: p.UnaliasCoordinates(to.Coordinates)}}
+				retVal[key] = wt
+			}
+		}
+	}
+	return retVal
+}
+
+// the network underlying an elementary layer, not a copy
+func (p *MultilayerNetwork) layerNetwork(coords string) (*Network, error) {
+	resolved, err := p.resolveCoordinates(coords)
+	if err != nil || !p.elementaryLayerExists(resolved) {
+		return nil, NewNetworkArgumentError(Sprintf("Layer %s not found in network", coords))
+	}
+	return p.elementaryLayers[resolved].g, nil
+}
+
+// apply intralayer changes through the multilayer methods so the vertex to layer bookkeeping stays consistent
+func (p *MultilayerNetwork) applyLayerDiff(coords string, diff *NetworkDiff) error {
+	for _, vertex := range diff.AddedVertices {
+		_, err := p.AddVertex(NodeLayerTuple{NodeId: vertex, Coordinates: coords})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range diff.RemovedEdges {
+		_, err := p.RemoveEdge(NodeLayerTuple{NodeId: edge.From, Coordinates: coords}, NodeLayerTuple{NodeId: edge.To, Coordinates: coords})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range diff.ChangedWeights {
+		from := NodeLayerTuple{NodeId: edge.From, Coordinates: coords}
+		to := NodeLayerTuple{NodeId: edge.To, Coordinates: coords}
+		_, err := p.RemoveEdge(from, to)
+		if err != nil {
+			return err
+		}
+		_, err = p.AddEdge(from, to, edge.NewWeight)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range diff.AddedEdges {
+		_, err := p.AddEdge(NodeLayerTuple{NodeId: edge.From, Coordinates: coords}, NodeLayerTuple{NodeId: edge.To, Coordinates: coords}, edge.NewWeight)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, vertex := range diff.RemovedVertices {
+		_, err := p.RemoveVertex(NodeLayerTuple{NodeId: vertex, Coordinates: coords})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -161,11 +161,10 @@ func processMLNetwork(reader *bufio.Reader) (*MultilayerNetwork, error) {
 
 func ReadAspects(reader *bufio.Reader) ([]string, [][]string) {
 	gmlTokenizer := NewGMLTokenizer()
-	aspectDictionary := gmlTokenizer.ReadListRecord(reader)
-	aspects := make([]string, 0)
+	// aspect order determines how coordinates are resolved, so it must follow the document
+	aspects, indexLists := gmlTokenizer.ReadOrderedListRecord(reader)
 	indices := make([][]string, 0)
-	for aspect, indexValues := range aspectDictionary {
-		aspects = append(aspects, aspect)
+	for _, indexValues := range indexLists {
 		indices = append(indices, strings.Split(indexValues, ","))
 	}
 	return aspects, indices
@@ -0,0 +1,91 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Descriptive statistics for a multilayer network, reported per elementary layer
+
+package Core
+
+import (
+	"bufio"
+	"encoding/json"
+	. "fmt"
+	"sort"
+)
+
+type MultilayerNetworkSummary struct {
+	Directed         bool                       `json:"directed"`
+	Aspects          []string                   `json:"aspects"`
+	ElementaryLayers int                        `json:"elementaryLayers"`
+	Order            int                        `json:"order"` // distinct vertex ids across all layers
+	NodeAligned      bool                       `json:"nodeAligned"`
+	InterlayerEdges  int                        `json:"interlayerEdges"`
+	Layers           map[string]*NetworkSummary `json:"layers"` // keyed by aspect coordinates
+}
+
+// Summarizes each elementary layer as Network.Summary does, along with the multilayer network as a whole
+func (p *MultilayerNetwork) Summary(eccentricitySamples int, seed int64) *MultilayerNetworkSummary {
+	retVal := new(MultilayerNetworkSummary)
+	retVal.Directed = p.directed
+	retVal.Aspects = p.aspects
+	retVal.ElementaryLayers = len(p.elementaryLayers)
+	retVal.Order = p.Order()
+	retVal.NodeAligned = p.IsNodeAligned()
+	retVal.InterlayerEdges = len(p.interlayerEdges())
+	retVal.Layers = make(map[string]*NetworkSummary, len(p.elementaryLayers))
+	for coords, layer := range p.elementaryLayers {
+		retVal.Layers[p.UnaliasCoordinates(coords)] = layer.g.Summary(eccentricitySamples, seed)
+	}
+	return retVal
+}
+
+// Writes the network-wide figures followed by a table for each layer, in order of coordinates
+func (summary *MultilayerNetworkSummary) List(writer *bufio.Writer) error {
+	_, err := Fprintf(writer, "%-24s%t\n", "directed", summary.Directed)
+	if err != nil {
+		return err
+	}
+	_, _ = Fprintf(writer, "%-24s%v\n", "aspects", summary.Aspects)
+	_, _ = Fprintf(writer, "%-24s%d\n", "elementary layers", summary.ElementaryLayers)
+	_, _ = Fprintf(writer, "%-24s%d\n", "order", summary.Order)
+	_, _ = Fprintf(writer, "%-24s%t\n", "node-aligned", summary.NodeAligned)
+	_, _ = Fprintf(writer, "%-24s%d\n", "interlayer edges", summary.InterlayerEdges)
+
+	coords := make([]string, 0, len(summary.Layers))
+	for c := range summary.Layers {
+		coords = append(coords, c)
+	}
+	sort.Strings(coords)
+	for _, c := range coords {
+		_, _ = Fprintln(writer, "layer "+c)
+		summary.Layers[c].listRows(writer, "\t")
+	}
+	return writer.Flush()
+}
+
+func (summary *MultilayerNetworkSummary) ListJSON(writer *bufio.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(summary)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
@@ -257,6 +257,11 @@ func (network *Network) AddEdge(from uint32, to uint32,  weight float32) error {
 
 func (network *Network) RemoveEdge(from uint32 , to uint32) {
 	if network.HasEdge(from, to) {
+		_, contained := network.outEdges[from][to]
+		if !contained && !network.Directed() {
+			// undirected edge held in the opposite orientation
+			from, to = to, from
+		}
 		neighbors := network.outEdges[from]
 		delete(neighbors, to)
 		delete(network.inEdges[to], from)
@@ -436,7 +441,7 @@ func (network *Network) Clone() *Network {
 		for k, v := range network.inEdges[key] {
 			sources[k] = v
 		}
-		retVal.inEdges[key] = targets
+		retVal.inEdges[key] = sources
 	}
 
 	return retVal
@@ -0,0 +1,281 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Structural differences between two networks, usable as a patch against the older network
+
+package Core
+
+import (
+	"bufio"
+	"encoding/json"
+	. "fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// a single edge that was added, removed, or had its weight changed
+// OldWeight is zero for added edges, NewWeight is zero for removed edges
+type EdgeChange struct {
+	From      uint32  `json:"from"`
+	To        uint32  `json:"to"`
+	OldWeight float32 `json:"oldWeight"`
+	NewWeight float32 `json:"newWeight"`
+}
+
+// change set taking one network (older) to another (newer)
+type NetworkDiff struct {
+	Directed        bool         `json:"directed"`
+	AddedVertices   []uint32     `json:"addedVertices"`
+	RemovedVertices []uint32     `json:"removedVertices"`
+	AddedEdges      []EdgeChange `json:"addedEdges"`
+	RemovedEdges    []EdgeChange `json:"removedEdges"`
+	ChangedWeights  []EdgeChange `json:"changedWeights"`
+}
+
+// Computes the change set from older to newer.  Weights are considered changed only if they differ by more than tolerance.
+// Both networks must agree on directedness.
+func Diff(older *Network, newer *Network, tolerance float32) (*NetworkDiff, error) {
+	if older == nil || newer == nil {
+		return nil, NewNetworkArgumentNullError("Both networks must be non-null")
+	}
+
+	if older.Directed() != newer.Directed() {
+		return nil, NewNetworkArgumentError("Both networks must have the same value of directed")
+	}
+
+	retVal := newNetworkDiff(older.Directed())
+
+	for vertex := range newer.outEdges {
+		if !older.HasVertex(vertex) {
+			retVal.AddedVertices = append(retVal.AddedVertices, vertex)
+		}
+	}
+
+	for vertex := range older.outEdges {
+		if !newer.HasVertex(vertex) {
+			retVal.RemovedVertices = append(retVal.RemovedVertices, vertex)
+		}
+	}
+
+	// each edge, directed or not, is held exactly once in outEdges, and HasEdge/EdgeWeight account for the orientation
+	// of undirected edges, so a single pass over each side suffices
+	for from, targets := range older.outEdges {
+		for to, wt := range targets {
+			if !newer.HasEdge(from, to) {
+				retVal.RemovedEdges = append(retVal.RemovedEdges, EdgeChange{From: from, To: to, OldWeight: wt})
+				continue
+			}
+			newWt := newer.EdgeWeight(from, to)
+			if math.Abs(float64(newWt-wt)) > float64(tolerance) {
+				retVal.ChangedWeights = append(retVal.ChangedWeights, EdgeChange{From: from, To: to, OldWeight: wt, NewWeight: newWt})
+			}
+		}
+	}
+
+	for from, targets := range newer.outEdges {
+		for to, wt := range targets {
+			if !older.HasEdge(from, to) {
+				retVal.AddedEdges = append(retVal.AddedEdges, EdgeChange{From: from, To: to, NewWeight: wt})
+			}
+		}
+	}
+
+	retVal.sort()
+	return retVal, nil
+}
+
+func newNetworkDiff(directed bool) *NetworkDiff {
+	retVal := new(NetworkDiff)
+	retVal.Directed = directed
+	retVal.AddedVertices = make([]uint32, 0)
+	retVal.RemovedVertices = make([]uint32, 0)
+	retVal.AddedEdges = make([]EdgeChange, 0)
+	retVal.RemovedEdges = make([]EdgeChange, 0)
+	retVal.ChangedWeights = make([]EdgeChange, 0)
+	return retVal
+}
+
+func (diff *NetworkDiff) IsEmpty() bool {
+	return len(diff.AddedVertices) == 0 && len(diff.RemovedVertices) == 0 && len(diff.AddedEdges) == 0 &&
+		len(diff.RemovedEdges) == 0 && len(diff.ChangedWeights) == 0
+}
+
+// Applies the change set to G, which should be the older network the diff was computed against.
+// The diff is checked against G before anything is modified, so G is left untouched if an error is returned.
+func (diff *NetworkDiff) Apply(G *Network) error {
+	if G == nil {
+		return NewNetworkArgumentNullError("Network must be non-null")
+	}
+
+	err := diff.validate(G)
+	if err != nil {
+		return err
+	}
+
+	for _, vertex := range diff.AddedVertices {
+		G.AddVertex(vertex)
+	}
+
+	for _, edge := range diff.RemovedEdges {
+		G.RemoveEdge(edge.From, edge.To)
+	}
+
+	// AddEdge leaves an existing edge alone, so a weight change is a removal followed by an add
+	for _, edge := range diff.ChangedWeights {
+		G.RemoveEdge(edge.From, edge.To)
+		_ = G.AddEdge(edge.From, edge.To, edge.NewWeight)
+	}
+
+	for _, edge := range diff.AddedEdges {
+		_ = G.AddEdge(edge.From, edge.To, edge.NewWeight)
+	}
+
+	for _, vertex := range diff.RemovedVertices {
+		G.RemoveVertex(vertex)
+	}
+
+	return nil
+}
+
+// Writes the change set as text, one change per line.  Lines begin with + (added), - (removed), or ~ (weight changed)
+// followed by the kind of item; fields are separated by delimiter.
+func (diff *NetworkDiff) List(writer *bufio.Writer, delimiter string) error {
+	var err error
+	if diff.Directed {
+		_, err = Fprintln(writer, "directed 1")
+	} else {
+		_, err = Fprintln(writer, "directed 0")
+	}
+	if err != nil {
+		return err
+	}
+
+	diff.listChanges(writer, delimiter, "")
+	return writer.Flush()
+}
+
+// the change lines without the directed header, each prefixed by indent
+func (diff *NetworkDiff) listChanges(writer *bufio.Writer, delimiter string, indent string) {
+	for _, vertex := range diff.AddedVertices {
+		_, _ = Fprintln(writer, Sprintf("%s+vertex%s%d", indent, delimiter, vertex))
+	}
+	for _, vertex := range diff.RemovedVertices {
+		_, _ = Fprintln(writer, Sprintf("%s-vertex%s%d", indent, delimiter, vertex))
+	}
+	for _, edge := range diff.AddedEdges {
+		_, _ = Fprintln(writer, Sprintf("%s+edge%s%d%s%d%s%s", indent, delimiter, edge.From, delimiter, edge.To, delimiter, formatWeight(edge.NewWeight)))
+	}
+	for _, edge := range diff.RemovedEdges {
+		_, _ = Fprintln(writer, Sprintf("%s-edge%s%d%s%d%s%s", indent, delimiter, edge.From, delimiter, edge.To, delimiter, formatWeight(edge.OldWeight)))
+	}
+	for _, edge := range diff.ChangedWeights {
+		_, _ = Fprintln(writer, Sprintf("%s~edge%s%d%s%d%s%s%s%s", indent, delimiter, edge.From, delimiter, edge.To, delimiter, formatWeight(edge.OldWeight), delimiter, formatWeight(edge.NewWeight)))
+	}
+}
+
+func (diff *NetworkDiff) ListJSON(writer *bufio.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(diff)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func ReadNetworkDiffJSON(reader io.Reader) (*NetworkDiff, error) {
+	retVal := newNetworkDiff(false)
+	err := json.NewDecoder(reader).Decode(retVal)
+	if err != nil {
+		return nil, err
+	}
+	return retVal, nil
+}
+
+// make sure every removal refers to something in G and every addition to something that is not, that no edge added or
+// changed is a self-edge, and that no edge is added twice
+func (diff *NetworkDiff) validate(G *Network) error {
+	if G.Directed() != diff.Directed {
+		return NewNetworkArgumentError("The network and the diff must have the same value of directed")
+	}
+
+	for _, vertex := range diff.AddedVertices {
+		if G.HasVertex(vertex) {
+			return NewNetworkArgumentError(Sprintf("Vertex %d to be added already exists", vertex))
+		}
+	}
+	for _, vertex := range diff.RemovedVertices {
+		if !G.HasVertex(vertex) {
+			return NewNetworkArgumentError(Sprintf("Vertex %d to be removed does not exist", vertex))
+		}
+	}
+	// an edge listed twice would silently keep only the first weight; undirected edges are listed once in either orientation
+	added := make(map[[2]uint32]bool, len(diff.AddedEdges))
+	for _, edge := range diff.AddedEdges {
+		if edge.From == edge.To {
+			return NewNetworkArgumentError(Sprintf("Edge %d, %d to be added is a self-edge", edge.From, edge.To))
+		}
+		if G.HasEdge(edge.From, edge.To) {
+			return NewNetworkArgumentError(Sprintf("Edge %d, %d to be added already exists", edge.From, edge.To))
+		}
+		if added[[2]uint32{edge.From, edge.To}] || (!diff.Directed && added[[2]uint32{edge.To, edge.From}]) {
+			return NewNetworkArgumentError(Sprintf("Edge %d, %d to be added is listed more than once", edge.From, edge.To))
+		}
+		added[[2]uint32{edge.From, edge.To}] = true
+	}
+	for _, edge := range diff.RemovedEdges {
+		if !G.HasEdge(edge.From, edge.To) {
+			return NewNetworkArgumentError(Sprintf("Edge %d, %d to be removed does not exist", edge.From, edge.To))
+		}
+	}
+	for _, edge := range diff.ChangedWeights {
+		if edge.From == edge.To {
+			return NewNetworkArgumentError(Sprintf("Edge %d, %d to be changed is a self-edge", edge.From, edge.To))
+		}
+		if !G.HasEdge(edge.From, edge.To) {
+			return NewNetworkArgumentError(Sprintf("Edge %d, %d to be changed does not exist", edge.From, edge.To))
+		}
+	}
+	return nil
+}
+
+func (diff *NetworkDiff) sort() {
+	sort.Slice(diff.AddedVertices, func(i, j int) bool { return diff.AddedVertices[i] < diff.AddedVertices[j] })
+	sort.Slice(diff.RemovedVertices, func(i, j int) bool { return diff.RemovedVertices[i] < diff.RemovedVertices[j] })
+	sortEdgeChanges(diff.AddedEdges)
+	sortEdgeChanges(diff.RemovedEdges)
+	sortEdgeChanges(diff.ChangedWeights)
+}
+
+func sortEdgeChanges(edges []EdgeChange) {
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		return edges[i].To < edges[j].To
+	})
+}
+
+func formatWeight(wt float32) string {
+	return strconv.FormatFloat(float64(wt), 'f', -1, 32)
+}
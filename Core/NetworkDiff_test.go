@@ -0,0 +1,282 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// network diff tests
+
+package Core
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestDiffBasic(t *testing.T) {
+	older := makeSimple(true)
+	newer := makeSimple(true)
+
+	newer.RemoveEdge(1, 2)
+	newer.RemoveVertex(5)
+	_ = newer.AddEdge(6, 7, 2.0)
+	newer.RemoveEdge(3, 4)
+	_ = newer.AddEdge(3, 4, 1.5)
+	newer.RemoveEdge(1, 3)
+	_ = newer.AddEdge(1, 3, 1.00001)
+
+	diff, err := Diff(older, newer, 0.001)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.AddedVertices) != 1 || diff.AddedVertices[0] != 7 {
+		t.Errorf("Expected vertex 7 to be added, found %v", diff.AddedVertices)
+	}
+	if len(diff.RemovedVertices) != 1 || diff.RemovedVertices[0] != 5 {
+		t.Errorf("Expected vertex 5 to be removed, found %v", diff.RemovedVertices)
+	}
+	// 1->2 plus the three edges incident to 5
+	if len(diff.RemovedEdges) != 4 {
+		t.Errorf("Expected 4 removed edges, found %d", len(diff.RemovedEdges))
+	}
+	if len(diff.AddedEdges) != 1 || diff.AddedEdges[0].From != 6 || diff.AddedEdges[0].To != 7 {
+		t.Errorf("Expected edge 6, 7 to be added, found %v", diff.AddedEdges)
+	}
+	if len(diff.ChangedWeights) != 1 || diff.ChangedWeights[0].NewWeight != 1.5 {
+		t.Errorf("Expected only the weight of 3, 4 to change, found %v", diff.ChangedWeights)
+	}
+
+	err = diff.Apply(older)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := Diff(older, newer, 0.001)
+	if !after.IsEmpty() {
+		t.Error("Patched network differs from the newer network")
+	}
+
+	// the patch no longer applies to the patched network
+	if diff.Apply(older) == nil {
+		t.Error("Expected an error applying the diff a second time")
+	}
+}
+
+func TestApplyInvalidLeavesNetworkUnchanged(t *testing.T) {
+	for _, directed := range []bool{true, false} {
+		invalid := [][]EdgeChange{
+			{{From: 3, To: 3, NewWeight: 1.0}},
+			{{From: 6, To: 3, NewWeight: 1.0}, {From: 6, To: 3, NewWeight: 2.0}},
+		}
+		if !directed {
+			invalid = append(invalid, []EdgeChange{{From: 6, To: 3, NewWeight: 1.0}, {From: 3, To: 6, NewWeight: 2.0}})
+		}
+		for _, added := range invalid {
+			G := makeSimple(directed)
+			diff := newNetworkDiff(directed)
+			diff.AddedVertices = append(diff.AddedVertices, 9)
+			diff.RemovedEdges = append(diff.RemovedEdges, EdgeChange{From: 1, To: 2, OldWeight: 1.0})
+			diff.AddedEdges = append(diff.AddedEdges, added...)
+			if diff.Apply(G) == nil {
+				t.Errorf("Expected an error adding %v", added)
+			}
+			if after, _ := Diff(makeSimple(directed), G, 0); !after.IsEmpty() {
+				t.Errorf("Expected the network to be unchanged after failing to add %v", added)
+			}
+		}
+
+		G := makeSimple(directed)
+		diff := newNetworkDiff(directed)
+		diff.RemovedEdges = append(diff.RemovedEdges, EdgeChange{From: 1, To: 2, OldWeight: 1.0})
+		diff.ChangedWeights = append(diff.ChangedWeights, EdgeChange{From: 3, To: 3, OldWeight: 1.0, NewWeight: 2.0})
+		if diff.Apply(G) == nil {
+			t.Error("Expected an error changing the weight of a self-edge")
+		}
+		if after, _ := Diff(makeSimple(directed), G, 0); !after.IsEmpty() {
+			t.Error("Expected the network to be unchanged after failing to change a self-edge")
+		}
+	}
+}
+
+func TestDiffUndirected(t *testing.T) {
+	older := makeSimple(false)
+	newer := NewNetwork(false)
+	// same edges, opposite orientation
+	for _, from := range older.Vertices(true) {
+		for to, wt := range older.outEdges[from] {
+			_ = newer.AddEdge(to, from, wt)
+		}
+	}
+
+	diff, err := Diff(older, newer, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.IsEmpty() {
+		t.Errorf("Expected no differences between reversed undirected networks")
+	}
+
+	newer.RemoveEdge(1, 2)
+	diff, _ = Diff(older, newer, 0)
+	if len(diff.RemovedEdges) != 1 {
+		t.Errorf("Expected one removed edge, found %d", len(diff.RemovedEdges))
+	}
+
+	_, err = Diff(older, makeSimple(true), 0)
+	if err == nil {
+		t.Error("Expected an error comparing directed and undirected networks")
+	}
+}
+
+func TestDiffSerialization(t *testing.T) {
+	older := makeSimple(true)
+	newer := makeSimple(true)
+	_ = newer.AddEdge(6, 1, 0.5)
+	newer.RemoveVertex(4)
+
+	diff, _ := Diff(older, newer, 0)
+
+	var buf bytes.Buffer
+	err := diff.ListJSON(bufio.NewWriter(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadNetworkDiffJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = read.Apply(older)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := Diff(older, newer, 0)
+	if !after.IsEmpty() {
+		t.Error("Network patched from JSON differs from the newer network")
+	}
+
+	buf.Reset()
+	err = diff.List(bufio.NewWriter(&buf), "|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("+edge|6|1|0.5")) || !bytes.Contains(buf.Bytes(), []byte("-vertex|4")) {
+		t.Errorf("Text listing missing expected lines:\n%s", buf.String())
+	}
+}
+
+func TestDiffMultilayer(t *testing.T) {
+	older, err := ReadMultilayerNetworkFromFile("multilayer_test.gml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer, _ := ReadMultilayerNetworkFromFile("multilayer_test.gml")
+
+	diff, err := DiffMultilayer(older, newer, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.IsEmpty() {
+		t.Error("Expected no differences between copies of the same network")
+	}
+
+	layers := newer.ElementaryLayers()
+	vertices, _ := newer.VerticesInLayer(layers[0])
+	from := NodeLayerTuple{NodeId: vertices[0], Coordinates: layers[0]}
+	to := NodeLayerTuple{NodeId: vertices[0] + 1000, Coordinates: layers[len(layers)-1]}
+	_, _ = newer.AddVertex(to)
+	_, err = newer.AddEdge(from, to, 3.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, _ = DiffMultilayer(older, newer, 0)
+	if len(diff.AddedInterlayerEdges) != 1 || len(diff.Layers) != 1 {
+		t.Errorf("Expected one added interlayer edge and one changed layer, found %d and %d", len(diff.AddedInterlayerEdges), len(diff.Layers))
+	}
+
+	err = diff.Apply(older)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := DiffMultilayer(older, newer, 0)
+	if !after.IsEmpty() {
+		t.Error("Patched multilayer network differs from the newer network")
+	}
+}
+
+func TestApplyMultilayerAllOrNothing(t *testing.T) {
+	older, err := ReadMultilayerNetworkFromFile("multilayer_test.gml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer, _ := ReadMultilayerNetworkFromFile("multilayer_test.gml")
+	layers := newer.ElementaryLayers()
+	vertices, _ := newer.VerticesInLayer(layers[0])
+	from := NodeLayerTuple{NodeId: vertices[0], Coordinates: layers[0]}
+	to := NodeLayerTuple{NodeId: vertices[0] + 1000, Coordinates: layers[len(layers)-1]}
+	_, _ = newer.AddVertex(to)
+	_, _ = newer.AddEdge(from, to, 3.0)
+	_, _ = newer.AddEdge(from, NodeLayerTuple{NodeId: vertices[0] + 3000, Coordinates: layers[0]}, 1.0)
+
+	// an interlayer edge absent from older, so removing it or changing its weight must fail before the layer change is applied
+	missing := InterlayerEdgeChange{From: from, To: NodeLayerTuple{NodeId: vertices[0] + 2000, Coordinates: layers[len(layers)-1]}, OldWeight: 1.0, NewWeight: 2.0}
+	for _, corrupt := range []func(*MultilayerNetworkDiff){
+		func(diff *MultilayerNetworkDiff) {
+			diff.RemovedInterlayerEdges = append(diff.RemovedInterlayerEdges, missing)
+		},
+		func(diff *MultilayerNetworkDiff) {
+			diff.ChangedInterlayerWeights = append(diff.ChangedInterlayerWeights, missing)
+		},
+		// a self-edge in the last layer changed, after the others have been applied
+		func(diff *MultilayerNetworkDiff) {
+			keys := diff.sortedLayerKeys(diff.Layers)
+			last := diff.Layers[keys[len(keys)-1]]
+			last.AddedEdges = append(last.AddedEdges, EdgeChange{From: vertices[0], To: vertices[0], NewWeight: 1.0})
+		},
+	} {
+		diff, _ := DiffMultilayer(older, newer, 0)
+		if len(diff.Layers) < 2 {
+			t.Fatalf("Expected two changed layers, found %d", len(diff.Layers))
+		}
+		corrupt(diff)
+		if err = diff.Apply(older); err == nil {
+			t.Error("Expected an error applying an invalid change")
+		}
+		unchanged, _ := ReadMultilayerNetworkFromFile("multilayer_test.gml")
+		if after, _ := DiffMultilayer(unchanged, older, 0); !after.IsEmpty() {
+			t.Error("Expected the multilayer network to be unchanged after a failed Apply")
+		}
+	}
+
+	// a self-edge in an added layer, which is applied before any other change
+	removed, _ := ReadMultilayerNetworkFromFile("multilayer_test.gml")
+	_, _ = removed.RemoveElementaryLayer(layers[len(layers)-1])
+	diff, _ := DiffMultilayer(removed, older, 0)
+	added, ok := diff.AddedLayers[layers[len(layers)-1]]
+	if !ok {
+		t.Fatalf("Expected layer %s to be added", layers[len(layers)-1])
+	}
+	added.AddedEdges = append(added.AddedEdges, EdgeChange{From: vertices[0], To: vertices[0], NewWeight: 1.0})
+	if err = diff.Apply(removed); err == nil {
+		t.Error("Expected an error applying a self-edge in an added layer")
+	}
+	if removed.HasElementaryLayer(layers[len(layers)-1]) {
+		t.Error("Expected the added layer to be absent after a failed Apply")
+	}
+}
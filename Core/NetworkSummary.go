@@ -0,0 +1,498 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Descriptive statistics for a network, gathered into a summary that can be written as text or JSON
+// Distances (eccentricity, diameter, radius) are hop counts within the largest weakly connected component with edge direction ignored.
+// Degree assortativity follows Newman, M. E. J., "Mixing patterns in networks", Physical Review E 67, 026126, 2003, with the four
+// directed variants named source degree-target degree, e.g., OutIn correlates the out-degree of each edge's source with the in-degree of its target.
+// Coefficients that are undefined (e.g., assortativity when every degree is equal) are reported as zero.
+
+package Core
+
+import (
+	"bufio"
+	"encoding/json"
+	. "fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+type DistributionSummary struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	StdDev float64 `json:"stdDev"`
+}
+
+type DegreeDistribution struct {
+	Summary DistributionSummary `json:"summary"`
+	Counts  map[int]int         `json:"counts"` // number of vertices having each degree
+}
+
+type DegreeAssortativity struct {
+	OutIn  float64 `json:"outIn"`
+	InIn   float64 `json:"inIn"`
+	OutOut float64 `json:"outOut"`
+	InOut  float64 `json:"inOut"`
+}
+
+// For undirected networks, the in, out, and total degree (and strength) distributions are all the vertex degree (strength),
+// every assortativity variant is the undirected coefficient, and there are as many strong components as weak components.
+type NetworkSummary struct {
+	Directed         bool                `json:"directed"`
+	Order            int                 `json:"order"`
+	Size             int                 `json:"size"`
+	Density          float64             `json:"density"`
+	InDegree         DegreeDistribution  `json:"inDegree"`
+	OutDegree        DegreeDistribution  `json:"outDegree"`
+	TotalDegree      DegreeDistribution  `json:"totalDegree"`
+	InStrength       DistributionSummary `json:"inStrength"`
+	OutStrength      DistributionSummary `json:"outStrength"`
+	TotalStrength    DistributionSummary `json:"totalStrength"`
+	Reciprocity      float64             `json:"reciprocity"`
+	Assortativity    DegreeAssortativity `json:"assortativity"`
+	Diameter         int                 `json:"diameter"`
+	Radius           int                 `json:"radius"`
+	DistancesExact   bool                `json:"distancesExact"` // if false, Diameter is a lower bound and Radius an upper bound
+	WeakComponents   int                 `json:"weakComponents"`
+	StrongComponents int                 `json:"strongComponents"`
+	LargestComponent int                 `json:"largestComponent"` // order of the largest weakly connected component
+	IsolatedVertices int                 `json:"isolatedVertices"`
+}
+
+// Computes the summary statistics of the network.  If eccentricitySamples is positive and smaller than the largest component,
+// diameter and radius are estimated from breadth-first searches rooted at that many randomly chosen vertices (plus a double sweep
+// from the farthest vertex found), seeded by seed; otherwise every vertex of the largest component is used and the distances are exact.
+func (network *Network) Summary(eccentricitySamples int, seed int64) *NetworkSummary {
+	retVal := new(NetworkSummary)
+	retVal.Directed = network.directed
+	retVal.Order = network.Order()
+	retVal.Size = network.Size()
+	if retVal.Order > 1 {
+		retVal.Density = network.Density()
+	}
+
+	vertices := network.Vertices(true)
+	inDegrees := make([]float64, len(vertices))
+	outDegrees := make([]float64, len(vertices))
+	totalDegrees := make([]float64, len(vertices))
+	inStrengths := make([]float64, len(vertices))
+	outStrengths := make([]float64, len(vertices))
+	totalStrengths := make([]float64, len(vertices))
+	for i, v := range vertices {
+		if network.directed {
+			inDegrees[i] = float64(network.InDegree(v))
+			outDegrees[i] = float64(network.OutDegree(v))
+			inStrengths[i] = float64(network.InWeights(v))
+			outStrengths[i] = float64(network.OutWeights(v))
+			totalStrengths[i] = inStrengths[i] + outStrengths[i]
+		} else {
+			inDegrees[i] = float64(network.Degree(v))
+			outDegrees[i] = inDegrees[i]
+			inStrengths[i] = float64(network.OutWeights(v))
+			outStrengths[i] = inStrengths[i]
+			totalStrengths[i] = inStrengths[i]
+		}
+		totalDegrees[i] = float64(network.Degree(v))
+		if totalDegrees[i] == 0 {
+			retVal.IsolatedVertices++
+		}
+	}
+	retVal.InDegree = degreeDistribution(inDegrees)
+	retVal.OutDegree = degreeDistribution(outDegrees)
+	retVal.TotalDegree = degreeDistribution(totalDegrees)
+	retVal.InStrength = summarize(inStrengths)
+	retVal.OutStrength = summarize(outStrengths)
+	retVal.TotalStrength = summarize(totalStrengths)
+
+	retVal.Reciprocity = network.reciprocity()
+	retVal.Assortativity = network.degreeAssortativity()
+
+	components := network.weakComponents()
+	retVal.WeakComponents = len(components)
+	largest := make([]uint32, 0)
+	for _, component := range components {
+		if len(component) > len(largest) {
+			largest = component
+		}
+	}
+	retVal.LargestComponent = len(largest)
+	if network.directed {
+		retVal.StrongComponents = network.strongComponentCount()
+	} else {
+		retVal.StrongComponents = retVal.WeakComponents
+	}
+
+	retVal.Diameter, retVal.Radius, retVal.DistancesExact = network.estimateDistances(largest, eccentricitySamples, seed)
+	return retVal
+}
+
+// Writes the summary as a two column text table
+func (summary *NetworkSummary) List(writer *bufio.Writer) error {
+	summary.listRows(writer, "")
+	return writer.Flush()
+}
+
+func (summary *NetworkSummary) ListJSON(writer *bufio.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "\t")
+	err := encoder.Encode(summary)
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func (summary *NetworkSummary) listRows(writer *bufio.Writer, indent string) {
+	row := func(name string, value string) {
+		_, _ = Fprintf(writer, "%s%-24s%s\n", indent, name, value)
+	}
+	dist := func(d DistributionSummary) string {
+		return Sprintf("min %.4g  max %.4g  mean %.4f  median %.4g  sd %.4f", d.Min, d.Max, d.Mean, d.Median, d.StdDev)
+	}
+
+	row("directed", Sprintf("%t", summary.Directed))
+	row("order", Sprintf("%d", summary.Order))
+	row("size", Sprintf("%d", summary.Size))
+	row("density", Sprintf("%.6f", summary.Density))
+	if summary.Directed {
+		row("in-degree", dist(summary.InDegree.Summary))
+		row("out-degree", dist(summary.OutDegree.Summary))
+	}
+	row("degree", dist(summary.TotalDegree.Summary))
+	if summary.Directed {
+		row("in-strength", dist(summary.InStrength))
+		row("out-strength", dist(summary.OutStrength))
+	}
+	row("strength", dist(summary.TotalStrength))
+	row("reciprocity", Sprintf("%.4f", summary.Reciprocity))
+	if summary.Directed {
+		row("assortativity out-in", Sprintf("%.4f", summary.Assortativity.OutIn))
+		row("assortativity in-in", Sprintf("%.4f", summary.Assortativity.InIn))
+		row("assortativity out-out", Sprintf("%.4f", summary.Assortativity.OutOut))
+		row("assortativity in-out", Sprintf("%.4f", summary.Assortativity.InOut))
+	} else {
+		row("assortativity", Sprintf("%.4f", summary.Assortativity.OutIn))
+	}
+	qualifier := ""
+	if !summary.DistancesExact {
+		qualifier = " (estimate)"
+	}
+	row("diameter", Sprintf("%d%s", summary.Diameter, qualifier))
+	row("radius", Sprintf("%d%s", summary.Radius, qualifier))
+	row("weak components", Sprintf("%d", summary.WeakComponents))
+	if summary.Directed {
+		row("strong components", Sprintf("%d", summary.StrongComponents))
+	}
+	row("largest component", Sprintf("%d", summary.LargestComponent))
+	row("isolated vertices", Sprintf("%d", summary.IsolatedVertices))
+}
+
+func degreeDistribution(degrees []float64) DegreeDistribution {
+	counts := make(map[int]int)
+	for _, d := range degrees {
+		counts[int(d)]++
+	}
+	return DegreeDistribution{Summary: summarize(degrees), Counts: counts}
+}
+
+func summarize(values []float64) DistributionSummary {
+	retVal := DistributionSummary{}
+	if len(values) == 0 {
+		return retVal
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	retVal.Min = sorted[0]
+	retVal.Max = sorted[len(sorted)-1]
+	retVal.Mean = sum / float64(len(sorted))
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		retVal.Median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		retVal.Median = sorted[mid]
+	}
+	variance := 0.0
+	for _, v := range sorted {
+		variance += (v - retVal.Mean) * (v - retVal.Mean)
+	}
+	retVal.StdDev = math.Sqrt(variance / float64(len(sorted)))
+	return retVal
+}
+
+// fraction of directed edges whose reverse is also present; one for undirected networks
+func (network *Network) reciprocity() float64 {
+	if !network.directed {
+		return 1.0
+	}
+	edges := 0
+	reciprocated := 0
+	for from, targets := range network.outEdges {
+		for to := range targets {
+			edges++
+			if _, ok := network.outEdges[to][from]; ok {
+				reciprocated++
+			}
+		}
+	}
+	if edges == 0 {
+		return 0.0
+	}
+	return float64(reciprocated) / float64(edges)
+}
+
+func (network *Network) degreeAssortativity() DegreeAssortativity {
+	if !network.directed {
+		// each undirected edge contributes in both orientations
+		xs := make([]float64, 0, 2*network.Size())
+		ys := make([]float64, 0, 2*network.Size())
+		for from, targets := range network.outEdges {
+			for to := range targets {
+				df := float64(network.Degree(from))
+				dt := float64(network.Degree(to))
+				xs = append(xs, df, dt)
+				ys = append(ys, dt, df)
+			}
+		}
+		r := pearson(xs, ys)
+		return DegreeAssortativity{OutIn: r, InIn: r, OutOut: r, InOut: r}
+	}
+
+	outFrom := make([]float64, 0, network.Size())
+	inFrom := make([]float64, 0, network.Size())
+	outTo := make([]float64, 0, network.Size())
+	inTo := make([]float64, 0, network.Size())
+	for from, targets := range network.outEdges {
+		for to := range targets {
+			outFrom = append(outFrom, float64(len(network.outEdges[from])))
+			inFrom = append(inFrom, float64(len(network.inEdges[from])))
+			outTo = append(outTo, float64(len(network.outEdges[to])))
+			inTo = append(inTo, float64(len(network.inEdges[to])))
+		}
+	}
+	return DegreeAssortativity{OutIn: pearson(outFrom, inTo), InIn: pearson(inFrom, inTo), OutOut: pearson(outFrom, outTo), InOut: pearson(inFrom, outTo)}
+}
+
+// Pearson correlation coefficient, zero when either variable is constant
+func pearson(xs []float64, ys []float64) float64 {
+	n := float64(len(xs))
+	if n == 0 {
+		return 0.0
+	}
+	var sx, sy, sxx, syy, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		syy += ys[i] * ys[i]
+		sxy += xs[i] * ys[i]
+	}
+	cov := sxy/n - (sx/n)*(sy/n)
+	vx := sxx/n - (sx/n)*(sx/n)
+	vy := syy/n - (sy/n)*(sy/n)
+	if vx <= 1e-12 || vy <= 1e-12 {
+		return 0.0
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+// neighbors in either direction
+func (network *Network) undirectedAdjacent(vertex uint32) []uint32 {
+	retVal := make([]uint32, 0, len(network.outEdges[vertex])+len(network.inEdges[vertex]))
+	for to := range network.outEdges[vertex] {
+		retVal = append(retVal, to)
+	}
+	for from := range network.inEdges[vertex] {
+		if _, ok := network.outEdges[vertex][from]; !ok {
+			retVal = append(retVal, from)
+		}
+	}
+	return retVal
+}
+
+// weakly connected components (connected components if undirected), each sorted, in order of their smallest vertex
+func (network *Network) weakComponents() [][]uint32 {
+	seen := make(map[uint32]bool, network.Order())
+	retVal := make([][]uint32, 0)
+	for _, start := range network.Vertices(true) {
+		if seen[start] {
+			continue
+		}
+		seen[start] = true
+		component := []uint32{start}
+		for i := 0; i < len(component); i++ {
+			for _, n := range network.undirectedAdjacent(component[i]) {
+				if !seen[n] {
+					seen[n] = true
+					component = append(component, n)
+				}
+			}
+		}
+		sort.Slice(component, func(i, j int) bool { return component[i] < component[j] })
+		retVal = append(retVal, component)
+	}
+	return retVal
+}
+
+// number of strongly connected components, by an iterative version of Tarjan's algorithm
+func (network *Network) strongComponentCount() int {
+	index := make(map[uint32]int, network.Order())
+	lowlink := make(map[uint32]int, network.Order())
+	onStack := make(map[uint32]bool)
+	stack := make([]uint32, 0)
+	next := 0
+	count := 0
+
+	type frame struct {
+		vertex    uint32
+		neighbors []uint32
+		pos       int
+	}
+
+	for _, root := range network.Vertices(true) {
+		if _, ok := index[root]; ok {
+			continue
+		}
+		callStack := []*frame{{vertex: root, neighbors: network.outNeighborList(root)}}
+		index[root] = next
+		lowlink[root] = next
+		next++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(callStack) > 0 {
+			top := callStack[len(callStack)-1]
+			if top.pos < len(top.neighbors) {
+				w := top.neighbors[top.pos]
+				top.pos++
+				if _, ok := index[w]; !ok {
+					index[w] = next
+					lowlink[w] = next
+					next++
+					stack = append(stack, w)
+					onStack[w] = true
+					callStack = append(callStack, &frame{vertex: w, neighbors: network.outNeighborList(w)})
+				} else if onStack[w] && index[w] < lowlink[top.vertex] {
+					lowlink[top.vertex] = index[w]
+				}
+				continue
+			}
+
+			// finished with top
+			v := top.vertex
+			callStack = callStack[:len(callStack)-1]
+			if len(callStack) > 0 {
+				parent := callStack[len(callStack)-1].vertex
+				if lowlink[v] < lowlink[parent] {
+					lowlink[parent] = lowlink[v]
+				}
+			}
+			if lowlink[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					if w == v {
+						break
+					}
+				}
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func (network *Network) outNeighborList(vertex uint32) []uint32 {
+	retVal := make([]uint32, 0, len(network.outEdges[vertex]))
+	for to := range network.outEdges[vertex] {
+		retVal = append(retVal, to)
+	}
+	return retVal
+}
+
+// hop count eccentricity of start within its weak component, along with the farthest vertex found
+func (network *Network) eccentricity(start uint32) (int, uint32) {
+	dist := map[uint32]int{start: 0}
+	queue := []uint32{start}
+	farthest := start
+	for i := 0; i < len(queue); i++ {
+		v := queue[i]
+		for _, n := range network.undirectedAdjacent(v) {
+			if _, ok := dist[n]; !ok {
+				dist[n] = dist[v] + 1
+				if dist[n] > dist[farthest] {
+					farthest = n
+				}
+				queue = append(queue, n)
+			}
+		}
+	}
+	return dist[farthest], farthest
+}
+
+func (network *Network) estimateDistances(component []uint32, samples int, seed int64) (int, int, bool) {
+	if len(component) == 0 {
+		return 0, 0, true
+	}
+
+	roots := component
+	exact := true
+	if samples > 0 && samples < len(component) {
+		exact = false
+		r := rand.New(rand.NewSource(seed))
+		roots = make([]uint32, samples)
+		for i, k := range r.Perm(len(component))[:samples] {
+			roots[i] = component[k]
+		}
+	}
+
+	diameter := 0
+	radius := math.MaxInt32
+	var farthestOverall uint32
+	for _, root := range roots {
+		ecc, farthest := network.eccentricity(root)
+		if ecc > diameter {
+			diameter = ecc
+			farthestOverall = farthest
+		}
+		if ecc < radius {
+			radius = ecc
+		}
+	}
+
+	if !exact {
+		// double sweep: the vertex farthest from anything is a good root for a lower bound on the diameter
+		ecc, _ := network.eccentricity(farthestOverall)
+		if ecc > diameter {
+			diameter = ecc
+		}
+	}
+	return diameter, radius, exact
+}
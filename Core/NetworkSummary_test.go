@@ -0,0 +1,116 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// network summary tests
+
+package Core
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestSummaryUndirected(t *testing.T) {
+	G := makeSimple(false)
+	G.AddVertex(10)
+	_ = G.AddEdge(11, 12, 2.0)
+
+	summary := G.Summary(0, 1)
+	if summary.Order != 9 || summary.Size != 10 {
+		t.Errorf("Expected order 9 and size 10, found %d and %d", summary.Order, summary.Size)
+	}
+	if summary.WeakComponents != 3 || summary.StrongComponents != 3 || summary.LargestComponent != 6 {
+		t.Errorf("Expected components 3 (largest 6), found %d (largest %d)", summary.WeakComponents, summary.LargestComponent)
+	}
+	if summary.IsolatedVertices != 1 {
+		t.Errorf("Expected one isolated vertex, found %d", summary.IsolatedVertices)
+	}
+	// makeSimple: every vertex has degree 3 and the graph has diameter 2
+	if summary.Diameter != 2 || summary.Radius != 2 || !summary.DistancesExact {
+		t.Errorf("Expected exact diameter 2 and radius 2, found %d and %d", summary.Diameter, summary.Radius)
+	}
+	if summary.TotalDegree.Counts[3] != 6 || summary.TotalDegree.Counts[1] != 2 || summary.TotalDegree.Counts[0] != 1 {
+		t.Errorf("Unexpected degree distribution %v", summary.TotalDegree.Counts)
+	}
+	if summary.TotalStrength.Max != 3 {
+		t.Errorf("Expected maximum strength 3, found %f", summary.TotalStrength.Max)
+	}
+}
+
+func TestSummaryDirected(t *testing.T) {
+	// a directed 3-cycle with one reciprocated edge and a tail
+	G := NewNetwork(true)
+	_ = G.AddEdge(1, 2, 1)
+	_ = G.AddEdge(2, 3, 1)
+	_ = G.AddEdge(3, 1, 1)
+	_ = G.AddEdge(2, 1, 1)
+	_ = G.AddEdge(3, 4, 1)
+
+	summary := G.Summary(0, 1)
+	if math.Abs(summary.Reciprocity-0.4) > 1e-9 {
+		t.Errorf("Expected reciprocity 0.4, found %f", summary.Reciprocity)
+	}
+	if summary.StrongComponents != 2 || summary.WeakComponents != 1 {
+		t.Errorf("Expected 2 strong and 1 weak component, found %d and %d", summary.StrongComponents, summary.WeakComponents)
+	}
+	if summary.OutDegree.Summary.Max != 2 || summary.InDegree.Counts[0] != 0 {
+		t.Errorf("Unexpected directed degree distributions")
+	}
+
+	// a star is perfectly disassortative
+	S := NewNetwork(false)
+	for i := 1; i < 6; i++ {
+		_ = S.AddEdge(0, uint32(i), 1)
+	}
+	if a := S.Summary(0, 1).Assortativity.OutIn; math.Abs(a+1) > 1e-9 {
+		t.Errorf("Expected assortativity -1 for a star, found %f", a)
+	}
+}
+
+func TestSummaryReports(t *testing.T) {
+	var buf bytes.Buffer
+	err := makeSimple(true).Summary(2, 7).ListJSON(bufio.NewWriter(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("\"assortativity\"")) {
+		t.Error("JSON summary missing assortativity")
+	}
+
+	M, err := ReadMultilayerNetworkFromFile("multilayer_three_aspects.gml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := M.Summary(0, 1)
+	if len(summary.Layers) != summary.ElementaryLayers || summary.InterlayerEdges == 0 {
+		t.Errorf("Expected a summary per layer and some interlayer edges")
+	}
+	buf.Reset()
+	err = summary.List(bufio.NewWriter(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("layer I,A,1")) {
+		t.Errorf("Text report missing layer I,A,1:\n%s", buf.String())
+	}
+}
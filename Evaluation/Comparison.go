@@ -0,0 +1,344 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Comparison of two clusterings, e.g., detected communities against ground truth: normalized mutual information, the overlapping NMI of McDaid et al.,
+// the adjusted Rand index, the Omega index, and best-match F1 and Jaccard scores.  Clusterings are given in the form returned by ConcurrentSLPA.
+// The measures taking partitions treat a vertex found in only one of them as a community of its own in the other.
+
+package Evaluation
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+)
+
+// Normalized mutual information of two partitions, 2 I(a;b) / (H(a) + H(b)) after Danon et al.  Two partitions with a single community each
+// have NMI 1.
+func NMI(a map[int][]uint32, b map[int][]uint32) (float64, error) {
+	la, lb, n, err := partitionLabels(a, b)
+	if err != nil {
+		return 0, err
+	}
+	joint := make(map[[2]int]int)
+	ca := make(map[int]int)
+	cb := make(map[int]int)
+	for v, x := range la {
+		y := lb[v]
+		joint[[2]int{x, y}]++
+		ca[x]++
+		cb[y]++
+	}
+	N := float64(n)
+	ha, hb, mutual := 0.0, 0.0, 0.0
+	for _, count := range ca {
+		ha -= plogp(float64(count) / N)
+	}
+	for _, count := range cb {
+		hb -= plogp(float64(count) / N)
+	}
+	for pair, count := range joint {
+		pxy := float64(count) / N
+		mutual += pxy * math.Log2(pxy/(float64(ca[pair[0]])/N*float64(cb[pair[1]])/N))
+	}
+	if ha+hb == 0 {
+		return 1, nil
+	}
+	return 2 * mutual / (ha + hb), nil
+}
+
+// Overlapping normalized mutual information of McDaid, Aaron F., Greene, Derek, and Hurley, Neil, Normalized Mutual Information to Evaluate
+// Overlapping Community Finding Algorithms, arXiv:1110.2515, 2011, normalized by the larger entropy.  The vertices are those of either cover.
+func OverlappingNMI(a map[int][]uint32, b map[int][]uint32) (float64, error) {
+	universe := make(map[uint32]bool)
+	for _, cover := range []map[int][]uint32{a, b} {
+		for _, members := range cover {
+			for _, v := range members {
+				universe[v] = true
+			}
+		}
+	}
+	n := float64(len(universe))
+	if n == 0 {
+		return 0, Core.NewNetworkArgumentError("Clusterings hold no vertices")
+	}
+	setsA, setsB := coverSets(a), coverSets(b)
+
+	entropy := func(sets []map[uint32]bool) float64 {
+		retVal := 0.0
+		for _, set := range sets {
+			p := float64(len(set)) / n
+			retVal += h(p) + h(1-p)
+		}
+		return retVal
+	}
+	// H(X|Y): each community of X is described by the community of Y that says the most about it, provided some community is informative
+	conditional := func(xs []map[uint32]bool, ys []map[uint32]bool) float64 {
+		retVal := 0.0
+		for _, x := range xs {
+			px := float64(len(x)) / n
+			best := h(px) + h(1-px)
+			for _, y := range ys {
+				both := 0
+				for v := range x {
+					if y[v] {
+						both++
+					}
+				}
+				p11 := float64(both) / n
+				p10 := float64(len(x)-both) / n
+				p01 := float64(len(y)-both) / n
+				p00 := 1 - p11 - p10 - p01
+				if h(p11)+h(p00) < h(p01)+h(p10) {
+					continue
+				}
+				py := float64(len(y)) / n
+				if value := h(p11) + h(p10) + h(p01) + h(p00) - h(py) - h(1-py); value < best {
+					best = value
+				}
+			}
+			retVal += best
+		}
+		return retVal
+	}
+
+	ha, hb := entropy(setsA), entropy(setsB)
+	maximum := math.Max(ha, hb)
+	if maximum == 0 {
+		return 1, nil
+	}
+	mutual := 0.5 * (ha - conditional(setsA, setsB) + hb - conditional(setsB, setsA))
+	return mutual / maximum, nil
+}
+
+// Adjusted Rand index of two partitions, after Hubert and Arabie
+func ARI(a map[int][]uint32, b map[int][]uint32) (float64, error) {
+	la, lb, n, err := partitionLabels(a, b)
+	if err != nil {
+		return 0, err
+	}
+	joint := make(map[[2]int]int)
+	ca := make(map[int]int)
+	cb := make(map[int]int)
+	for v, x := range la {
+		joint[[2]int{x, lb[v]}]++
+		ca[x]++
+		cb[lb[v]]++
+	}
+	index, sumA, sumB := 0.0, 0.0, 0.0
+	for _, count := range joint {
+		index += choose2(count)
+	}
+	for _, count := range ca {
+		sumA += choose2(count)
+	}
+	for _, count := range cb {
+		sumB += choose2(count)
+	}
+	expected := sumA * sumB / choose2(n)
+	maximum := (sumA + sumB) / 2
+	if maximum == expected {
+		return 1, nil
+	}
+	return (index - expected) / (maximum - expected), nil
+}
+
+// Omega index of Collins, Linda M. and Dent, Clyde W., Omega: A General Formulation of the Rand Index of Cluster Recovery Suitable for
+// Non-disjoint Solutions, Multivariate Behavioral Research 23(2):231-242, 1988: the agreement, corrected for chance, on the number of communities
+// each pair of vertices shares.  For partitions it equals the adjusted Rand index.
+func Omega(a map[int][]uint32, b map[int][]uint32) (float64, error) {
+	universe := make(map[uint32]bool)
+	for _, cover := range []map[int][]uint32{a, b} {
+		for _, members := range cover {
+			for _, v := range members {
+				universe[v] = true
+			}
+		}
+	}
+	if len(universe) < 2 {
+		return 0, Core.NewNetworkArgumentError("Omega requires at least two vertices")
+	}
+	pairs := choose2(len(universe))
+	sharedA, sharedB := sharedCounts(a), sharedCounts(b)
+
+	// pairs sharing j communities in each cover, and pairs sharing j in both; pairs sharing none are whatever remains
+	countA := make(map[int]float64)
+	countB := make(map[int]float64)
+	observed, either := 0.0, float64(len(sharedA))
+	for pair, j := range sharedA {
+		countA[j]++
+		if sharedB[pair] == j {
+			observed++
+		}
+	}
+	for pair, j := range sharedB {
+		countB[j]++
+		if _, ok := sharedA[pair]; !ok {
+			either++
+		}
+	}
+	countA[0] = pairs - float64(len(sharedA))
+	countB[0] = pairs - float64(len(sharedB))
+	observed += pairs - either
+
+	expected := 0.0
+	for j, count := range countA {
+		expected += count * countB[j]
+	}
+	observed /= pairs
+	expected /= pairs * pairs
+	if expected == 1 {
+		return 1, nil
+	}
+	return (observed - expected) / (1 - expected), nil
+}
+
+// Best-match F1 score: each community of a is matched to the community of b with which it has the greatest F1 score, and vice versa, and the
+// two averages are averaged, after Yang, Jaewon and Leskovec, Jure, Overlapping Community Detection at Scale, WSDM 2013
+func BestMatchF1(a map[int][]uint32, b map[int][]uint32) (float64, error) {
+	return bestMatch(a, b, func(both int, x int, y int) float64 { return 2 * float64(both) / float64(x+y) })
+}
+
+// Best-match Jaccard score, computed as BestMatchF1 with the Jaccard similarity of the communities in place of F1
+func BestMatchJaccard(a map[int][]uint32, b map[int][]uint32) (float64, error) {
+	return bestMatch(a, b, func(both int, x int, y int) float64 { return float64(both) / float64(x+y-both) })
+}
+
+func bestMatch(a map[int][]uint32, b map[int][]uint32, score func(both int, x int, y int) float64) (float64, error) {
+	setsA, setsB := coverSets(a), coverSets(b)
+	if len(setsA) == 0 || len(setsB) == 0 {
+		return 0, Core.NewNetworkArgumentError("Both clusterings must hold at least one non-empty community")
+	}
+	average := func(xs []map[uint32]bool, ys []map[uint32]bool) float64 {
+		total := 0.0
+		for _, x := range xs {
+			best := 0.0
+			for _, y := range ys {
+				both := 0
+				for v := range x {
+					if y[v] {
+						both++
+					}
+				}
+				if s := score(both, len(x), len(y)); s > best {
+					best = s
+				}
+			}
+			total += best
+		}
+		return total / float64(len(xs))
+	}
+	return (average(setsA, setsB) + average(setsB, setsA)) / 2, nil
+}
+
+// the community of every vertex in either partition, with a new singleton community for a vertex missing from one; an error for covers
+func partitionLabels(a map[int][]uint32, b map[int][]uint32) (map[uint32]int, map[uint32]int, int, error) {
+	labels := func(partition map[int][]uint32) (map[uint32]int, error) {
+		retVal := make(map[uint32]int)
+		for c, members := range partition {
+			for _, v := range members {
+				if _, ok := retVal[v]; ok {
+					return nil, Core.NewNetworkArgumentError(Sprintf("Vertex %d belongs to more than one community", v))
+				}
+				retVal[v] = c
+			}
+		}
+		return retVal, nil
+	}
+	la, err := labels(a)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	lb, err := labels(b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	// singletons take ids below every community id in use
+	next := func(l map[uint32]int) int {
+		retVal := 0
+		for _, c := range l {
+			if c < retVal {
+				retVal = c
+			}
+		}
+		return retVal - 1
+	}
+	nextA, nextB := next(la), next(lb)
+	for v := range la {
+		if _, ok := lb[v]; !ok {
+			lb[v] = nextB
+			nextB--
+		}
+	}
+	for v := range lb {
+		if _, ok := la[v]; !ok {
+			la[v] = nextA
+			nextA--
+		}
+	}
+	if len(la) < 2 {
+		return nil, nil, 0, Core.NewNetworkArgumentError("Comparing partitions requires at least two vertices")
+	}
+	return la, lb, len(la), nil
+}
+
+// the non-empty communities of a cover as sets
+func coverSets(cover map[int][]uint32) []map[uint32]bool {
+	retVal := make([]map[uint32]bool, 0, len(cover))
+	for _, members := range cover {
+		if len(members) > 0 {
+			retVal = append(retVal, vertexSet(members))
+		}
+	}
+	return retVal
+}
+
+// the number of communities shared by each pair of vertices sharing at least one; pairs are keyed low vertex first
+func sharedCounts(cover map[int][]uint32) map[[2]uint32]int {
+	retVal := make(map[[2]uint32]int)
+	for _, members := range cover {
+		set := vertexSet(members)
+		for u := range set {
+			for v := range set {
+				if u < v {
+					retVal[[2]uint32{u, v}]++
+				}
+			}
+		}
+	}
+	return retVal
+}
+
+func choose2(n int) float64 {
+	return float64(n) * float64(n-1) / 2
+}
+
+func plogp(p float64) float64 {
+	if p <= 0 {
+		return 0
+	}
+	return p * math.Log2(p)
+}
+
+func h(p float64) float64 {
+	return -plogp(p)
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Evaluation
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func randomPartition(n int, k int, r *rand.Rand) map[int][]uint32 {
+	retVal := make(map[int][]uint32)
+	for v := 0; v < n; v++ {
+		c := r.Intn(k)
+		retVal[c] = append(retVal[c], uint32(v))
+	}
+	return retVal
+}
+
+func TestComparisonIdentical(t *testing.T) {
+	a := map[int][]uint32{0: {0, 1, 2}, 1: {3, 4, 5}, 2: {6, 7}}
+	b := map[int][]uint32{7: {6, 7}, 8: {3, 4, 5}, 9: {0, 1, 2}}
+	measures := map[string]func(map[int][]uint32, map[int][]uint32) (float64, error){
+		"NMI": NMI, "OverlappingNMI": OverlappingNMI, "ARI": ARI, "Omega": Omega, "BestMatchF1": BestMatchF1, "BestMatchJaccard": BestMatchJaccard,
+	}
+	for name, measure := range measures {
+		value, err := measure(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(value-1) > 1e-12 {
+			t.Errorf("Expected %s of identical clusterings to be 1, found %f", name, value)
+		}
+	}
+}
+
+func TestComparisonKnownValues(t *testing.T) {
+	a := map[int][]uint32{0: {0, 1, 2}, 1: {3, 4, 5}}
+	b := map[int][]uint32{0: {0, 1}, 1: {2, 3, 4, 5}}
+
+	// contingency 2, 1, 3: index 4, expected 6 * 7 / 15, maximum 6.5
+	if ari, _ := ARI(a, b); math.Abs(ari-1.2/3.7) > 1e-12 {
+		t.Errorf("Expected ARI %f, found %f", 1.2/3.7, ari)
+	}
+	if f1, _ := BestMatchF1(a, b); math.Abs(f1-(0.8+6.0/7)/2) > 1e-12 {
+		t.Errorf("Expected best-match F1 %f, found %f", (0.8+6.0/7)/2, f1)
+	}
+	if jaccard, _ := BestMatchJaccard(a, b); math.Abs(jaccard-17.0/24) > 1e-12 {
+		t.Errorf("Expected best-match Jaccard 17/24, found %f", jaccard)
+	}
+
+	// H(a) = 1, H(b) = 0.918296, I = H(a) - H(a|b) = 1 - (4/6) * 0.811278
+	nmi, _ := NMI(a, b)
+	hb := -(1.0/3)*math.Log2(1.0/3) - (2.0/3)*math.Log2(2.0/3)
+	mutual := 1 - (4.0/6)*(-(1.0/4)*math.Log2(1.0/4)-(3.0/4)*math.Log2(3.0/4))
+	if expected := 2 * mutual / (1 + hb); math.Abs(nmi-expected) > 1e-12 {
+		t.Errorf("Expected NMI %f, found %f", expected, nmi)
+	}
+
+	// vertices missing from one partition are singletons there
+	c := map[int][]uint32{0: {0, 1, 2}}
+	d := map[int][]uint32{0: {0, 1, 2}, 1: {3}, 2: {4}}
+	if ari, _ := ARI(c, d); math.Abs(ari-1) > 1e-12 {
+		t.Errorf("Expected ARI 1 with missing vertices as singletons, found %f", ari)
+	}
+	if _, err := NMI(map[int][]uint32{0: {0, 1}, 1: {1, 2}}, a); err == nil {
+		t.Errorf("Expected an error comparing a cover with NMI")
+	}
+}
+
+func TestOmegaEqualsARI(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for trial := 0; trial < 20; trial++ {
+		a := randomPartition(30, 2+r.Intn(4), r)
+		b := randomPartition(30, 2+r.Intn(4), r)
+		ari, err := ARI(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		omega, err := Omega(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(ari-omega) > 1e-9 {
+			t.Errorf("Expected Omega to equal ARI for partitions, found %f and %f", omega, ari)
+		}
+		nmi, _ := NMI(a, b)
+		onmi, _ := OverlappingNMI(a, b)
+		if nmi < 0 || nmi > 1 || onmi < 0 || onmi > 1 {
+			t.Errorf("Expected NMI and overlapping NMI between 0 and 1, found %f and %f", nmi, onmi)
+		}
+	}
+}
+
+func TestOverlappingComparison(t *testing.T) {
+	truth := map[int][]uint32{0: {0, 1, 2, 3, 4}, 1: {4, 5, 6, 7, 8}}
+	near := map[int][]uint32{0: {0, 1, 2, 3, 4}, 1: {5, 6, 7, 8}}
+	far := map[int][]uint32{0: {0, 2, 4, 6, 8}, 1: {1, 3, 5, 7}}
+
+	nearNMI, _ := OverlappingNMI(truth, near)
+	farNMI, _ := OverlappingNMI(truth, far)
+	if nearNMI <= farNMI {
+		t.Errorf("Expected the nearer cover to score higher, found %f and %f", nearNMI, farNMI)
+	}
+	nearOmega, _ := Omega(truth, near)
+	farOmega, _ := Omega(truth, far)
+	if nearOmega <= farOmega {
+		t.Errorf("Expected the nearer cover to have the higher Omega, found %f and %f", nearOmega, farOmega)
+	}
+	if value, _ := OverlappingNMI(truth, truth); math.Abs(value-1) > 1e-12 {
+		t.Errorf("Expected overlapping NMI 1 for identical covers, found %f", value)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Quality of a clustering of a network, given in the form returned by ConcurrentSLPA: modularity, including the overlapping extension of Shen et al.,
+// conductance, coverage, and performance.  Vertices that belong to no community are treated as communities of their own.
+
+package Evaluation
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"sort"
+)
+
+// Modularity of a partition at the given resolution (1 for Newman and Girvan's modularity); directed networks use the directed modularity of
+// Leicht and Newman.  Returns an error if a vertex belongs to more than one community; use OverlappingModularity for covers.
+func Modularity(G *Core.Network, communities map[int][]uint32, resolution float64) (float64, error) {
+	memberships, err := membershipsOf(G, communities)
+	if err != nil {
+		return 0, err
+	}
+	for v, cs := range memberships {
+		if len(cs) > 1 {
+			return 0, Core.NewNetworkArgumentError(Sprintf("Vertex %d belongs to more than one community", v))
+		}
+	}
+	return overlappingModularity(G, memberships, resolution), nil
+}
+
+// Modularity of a cover after Shen, Huawei, Cheng, Xueqi, Cai, Kai, and Hu, Mao-Bin, Detect Overlapping and Hierarchical Community Structure in
+// Networks, Physica A 388(8):1706-1712, 2009: each pair of vertices contributes to the communities they share, weighted by 1 / (Ov Ow), where Ov is
+// the number of communities containing v.  For a partition this is the modularity.
+func OverlappingModularity(G *Core.Network, communities map[int][]uint32, resolution float64) (float64, error) {
+	memberships, err := membershipsOf(G, communities)
+	if err != nil {
+		return 0, err
+	}
+	return overlappingModularity(G, memberships, resolution), nil
+}
+
+// Conductance of each community: the weight of edges leaving it divided by the lesser of its volume and the volume of the rest of the network,
+// where volume is the total degree (strength) of the vertices.  A community with no edges has conductance 0.
+func Conductance(G *Core.Network, communities map[int][]uint32) (map[int]float64, error) {
+	if _, err := membershipsOf(G, communities); err != nil {
+		return nil, err
+	}
+	total := 0.0
+	for _, v := range G.Vertices(false) {
+		total += strength(G, v)
+	}
+
+	retVal := make(map[int]float64, len(communities))
+	for c, members := range communities {
+		set := vertexSet(members)
+		volume, cut := 0.0, 0.0
+		for v := range set {
+			volume += strength(G, v)
+			for n, wt := range G.GetNeighbors(v) {
+				if !set[n] {
+					cut += float64(wt)
+				}
+			}
+			if G.Directed() {
+				for n, wt := range G.GetSources(v) {
+					if !set[n] {
+						cut += float64(wt)
+					}
+				}
+			}
+		}
+		denominator := volume
+		if total-volume < denominator {
+			denominator = total - volume
+		}
+		if denominator > 0 {
+			retVal[c] = cut / denominator
+		} else {
+			retVal[c] = 0
+		}
+	}
+	return retVal, nil
+}
+
+// Fraction of the total edge weight on edges whose endpoints share a community
+func Coverage(G *Core.Network, communities map[int][]uint32) (float64, error) {
+	memberships, err := membershipsOf(G, communities)
+	if err != nil {
+		return 0, err
+	}
+	covered, total := 0.0, 0.0
+	forEachEdge(G, func(u uint32, v uint32, wt float64) {
+		total += wt
+		if shareCommunity(memberships, u, v) {
+			covered += wt
+		}
+	})
+	if total == 0 {
+		return 0, nil
+	}
+	return covered / total, nil
+}
+
+// Fraction of the pairs of vertices (ordered pairs on directed networks) correctly classified: joined by an edge and sharing a community, or
+// neither.  Edge weights are ignored.
+func Performance(G *Core.Network, communities map[int][]uint32) (float64, error) {
+	memberships, err := membershipsOf(G, communities)
+	if err != nil {
+		return 0, err
+	}
+	n := G.Order()
+	if n < 2 {
+		return 0, Core.NewNetworkArgumentError("Performance requires at least two vertices")
+	}
+	pairs := n * (n - 1)
+	if !G.Directed() {
+		pairs /= 2
+	}
+
+	// pairs sharing a community, counted once each
+	together := 0
+	vertices := G.Vertices(true)
+	for i, u := range vertices {
+		for _, v := range vertices[i+1:] {
+			if shareCommunity(memberships, u, v) {
+				together++
+			}
+		}
+	}
+	if G.Directed() {
+		together *= 2
+	}
+	intra, inter := 0, 0
+	forEachEdge(G, func(u uint32, v uint32, wt float64) {
+		if shareCommunity(memberships, u, v) {
+			intra++
+		} else {
+			inter++
+		}
+	})
+	// correct: edges within communities, plus pairs in different communities that are not joined
+	correct := intra + (pairs - together - inter)
+	return float64(correct) / float64(pairs), nil
+}
+
+func overlappingModularity(G *Core.Network, memberships map[uint32][]int, resolution float64) float64 {
+	// belonging of each vertex to each of its communities; vertices in no community form their own
+	belonging := func(v uint32) float64 {
+		if len(memberships[v]) == 0 {
+			return 1
+		}
+		return 1 / float64(len(memberships[v]))
+	}
+	communitiesOf := func(v uint32) []int {
+		if len(memberships[v]) == 0 {
+			return []int{-1 - int(v)}
+		}
+		return memberships[v]
+	}
+
+	m := 0.0
+	forEachEdge(G, func(u uint32, v uint32, wt float64) { m += wt })
+	if m == 0 {
+		return 0
+	}
+
+	// observed: edges within shared communities; expected: the null model summed over the members of each community
+	observed := 0.0
+	forEachEdge(G, func(u uint32, v uint32, wt float64) {
+		shared := 0
+		for _, c := range communitiesOf(u) {
+			for _, d := range communitiesOf(v) {
+				if c == d {
+					shared++
+				}
+			}
+		}
+		observed += wt * float64(shared) * belonging(u) * belonging(v)
+	})
+
+	outSum := make(map[int]float64)
+	inSum := make(map[int]float64)
+	for _, v := range G.Vertices(false) {
+		kout, kin := outStrength(G, v), inStrength(G, v)
+		for _, c := range communitiesOf(v) {
+			outSum[c] += kout * belonging(v)
+			inSum[c] += kin * belonging(v)
+		}
+	}
+	expected := 0.0
+	for c := range outSum {
+		expected += outSum[c] * inSum[c]
+	}
+	if G.Directed() {
+		return observed/m - resolution*expected/(m*m)
+	}
+	// undirected: each edge is observed once above, and strengths count both ends
+	return observed/m - resolution*expected/(4*m*m)
+}
+
+// the communities each vertex belongs to, ascending; an error if a community holds a vertex not in G or holds a vertex twice
+func membershipsOf(G *Core.Network, communities map[int][]uint32) (map[uint32][]int, error) {
+	if G == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network must be non-null")
+	}
+	retVal := make(map[uint32][]int, G.Order())
+	ids := make([]int, 0, len(communities))
+	for c := range communities {
+		ids = append(ids, c)
+	}
+	sort.Ints(ids)
+	for _, c := range ids {
+		seen := make(map[uint32]bool, len(communities[c]))
+		for _, v := range communities[c] {
+			if !G.HasVertex(v) {
+				return nil, Core.NewNetworkArgumentError(Sprintf("Vertex %d of community %d is not in the network", v, c))
+			}
+			if seen[v] {
+				return nil, Core.NewNetworkArgumentError(Sprintf("Vertex %d appears twice in community %d", v, c))
+			}
+			seen[v] = true
+			retVal[v] = append(retVal[v], c)
+		}
+	}
+	return retVal, nil
+}
+
+func shareCommunity(memberships map[uint32][]int, u uint32, v uint32) bool {
+	for _, c := range memberships[u] {
+		for _, d := range memberships[v] {
+			if c == d {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// calls visit once for each edge, once for each undirected edge
+func forEachEdge(G *Core.Network, visit func(u uint32, v uint32, wt float64)) {
+	for _, u := range G.Vertices(true) {
+		for v, wt := range G.GetNeighbors(u) {
+			if G.Directed() || u < v {
+				visit(u, v, float64(wt))
+			}
+		}
+	}
+}
+
+func outStrength(G *Core.Network, v uint32) float64 {
+	retVal := 0.0
+	for _, wt := range G.GetNeighbors(v) {
+		retVal += float64(wt)
+	}
+	return retVal
+}
+
+func inStrength(G *Core.Network, v uint32) float64 {
+	if !G.Directed() {
+		return outStrength(G, v)
+	}
+	retVal := 0.0
+	for _, wt := range G.GetSources(v) {
+		retVal += float64(wt)
+	}
+	return retVal
+}
+
+// total strength: in plus out on directed networks
+func strength(G *Core.Network, v uint32) float64 {
+	if G.Directed() {
+		return outStrength(G, v) + inStrength(G, v)
+	}
+	return outStrength(G, v)
+}
+
+func vertexSet(vertices []uint32) map[uint32]bool {
+	retVal := make(map[uint32]bool, len(vertices))
+	for _, v := range vertices {
+		retVal[v] = true
+	}
+	return retVal
+}
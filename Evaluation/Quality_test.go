@@ -0,0 +1,143 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Evaluation
+
+import (
+	"github.com/smohr1824/Networks/Algorithms"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"testing"
+)
+
+// two triangles, 0-1-2 and 3-4-5, joined by the edge 2-3
+func makeTwoTriangles(directed bool) *Core.Network {
+	G := Core.NewNetwork(directed)
+	for _, base := range []uint32{0, 3} {
+		_ = G.AddEdge(base, base+1, 1.0)
+		_ = G.AddEdge(base+1, base+2, 1.0)
+		_ = G.AddEdge(base+2, base, 1.0)
+	}
+	_ = G.AddEdge(2, 3, 1.0)
+	return G
+}
+
+func TestQualityTwoTriangles(t *testing.T) {
+	G := makeTwoTriangles(false)
+	communities := map[int][]uint32{0: {0, 1, 2}, 1: {3, 4, 5}}
+
+	q, err := Modularity(G, communities, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(q-5.0/14) > 1e-12 {
+		t.Errorf("Expected modularity 5/14, found %f", q)
+	}
+	if oq, _ := OverlappingModularity(G, communities, 1.0); math.Abs(oq-q) > 1e-12 {
+		t.Errorf("Expected overlapping modularity of a partition to equal its modularity, found %f and %f", oq, q)
+	}
+	conductance, err := Conductance(G, communities)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for c, value := range conductance {
+		if math.Abs(value-1.0/7) > 1e-12 {
+			t.Errorf("Expected conductance 1/7 for community %d, found %f", c, value)
+		}
+	}
+	if coverage, _ := Coverage(G, communities); math.Abs(coverage-6.0/7) > 1e-12 {
+		t.Errorf("Expected coverage 6/7, found %f", coverage)
+	}
+	if performance, _ := Performance(G, communities); math.Abs(performance-14.0/15) > 1e-12 {
+		t.Errorf("Expected performance 14/15, found %f", performance)
+	}
+
+	// one community holding everything has modularity 0 and full coverage
+	whole := map[int][]uint32{0: {0, 1, 2, 3, 4, 5}}
+	if q, _ := Modularity(G, whole, 1.0); math.Abs(q) > 1e-12 {
+		t.Errorf("Expected modularity 0 for a single community, found %f", q)
+	}
+	if coverage, _ := Coverage(G, whole); coverage != 1 {
+		t.Errorf("Expected coverage 1 for a single community, found %f", coverage)
+	}
+}
+
+func TestQualityDirected(t *testing.T) {
+	G := makeTwoTriangles(true)
+	communities := map[int][]uint32{0: {0, 1, 2}, 1: {3, 4, 5}}
+
+	// m = 7; out strengths (1,1,2) and (1,1,1), in strengths (1,1,1) and (2,1,1): Q = 6/7 - (4*3 + 3*4)/49
+	q, err := Modularity(G, communities, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := 6.0/7 - 24.0/49; math.Abs(q-expected) > 1e-12 {
+		t.Errorf("Expected directed modularity %f, found %f", expected, q)
+	}
+	// 30 ordered pairs: 6 arcs within, and 18 pairs apart less the one arc between
+	if performance, _ := Performance(G, communities); math.Abs(performance-23.0/30) > 1e-12 {
+		t.Errorf("Expected performance 23/30, found %f", performance)
+	}
+}
+
+func TestQualityOverlapping(t *testing.T) {
+	G := makeTwoTriangles(false)
+	cover := map[int][]uint32{0: {0, 1, 2, 3}, 1: {2, 3, 4, 5}}
+	if _, err := Modularity(G, cover, 1.0); err == nil {
+		t.Errorf("Expected an error for a vertex in two communities")
+	}
+	q, err := OverlappingModularity(G, cover, 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q <= 0 || q >= 1 {
+		t.Errorf("Expected overlapping modularity between 0 and 1, found %f", q)
+	}
+	if _, err := Coverage(G, map[int][]uint32{0: {0, 9}}); err == nil {
+		t.Errorf("Expected an error for a vertex not in the network")
+	}
+	if _, err := Conductance(nil, cover); err == nil {
+		t.Errorf("Expected an error for a nil network")
+	}
+}
+
+func TestModularityMatchesLouvain(t *testing.T) {
+	G := makeTwoTriangles(false)
+	for _, v := range []uint32{6, 7, 8} {
+		_ = G.AddEdge(v, (v-5)%3+6, 2.0)
+	}
+	_ = G.AddEdge(5, 6, 1.0)
+
+	options := Algorithms.NewLouvainOptions()
+	options.ConcurrentCount = 1
+	dendrogram, err := Algorithms.LouvainDendrogram(G, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	top := len(dendrogram.Levels) - 1
+	q, err := Modularity(G, dendrogram.Communities(top), 1.0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(q-dendrogram.Quality[top]) > 1e-9 {
+		t.Errorf("Expected the modularity Louvain reports, %f, found %f", dendrogram.Quality[top], q)
+	}
+}
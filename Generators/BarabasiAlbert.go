@@ -0,0 +1,72 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Barabási–Albert preferential attachment
+// Barabási, Albert-László and Albert, Réka, "Emergence of scaling in random networks", Science 286, 509-512, 1999
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+)
+
+// Generates an undirected scale-free network on vertices 0..n-1.  Growth starts from m isolated vertices; each new vertex
+// attaches to m distinct existing vertices chosen with probability proportional to their degree.  All edges have weight 1.
+func BarabasiAlbert(n int, m int, r *rand.Rand) (*Core.Network, error) {
+	if m < 1 || m >= n {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Attachment count must satisfy 1 <= m < n, saw m = %d, n = %d", m, n))
+	}
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+
+	G := newNetworkWithVertices(n, false)
+
+	// every vertex appears in repeated once per incident edge, so a uniform draw from it is a degree-proportional draw
+	repeated := make([]uint32, 0, 2*n*m)
+	targets := make([]uint32, m)
+	for i := 0; i < m; i++ {
+		targets[i] = uint32(i)
+	}
+
+	for source := m; source < n; source++ {
+		for _, target := range targets {
+			_ = G.AddEdge(uint32(source), target, 1.0)
+		}
+		repeated = append(repeated, targets...)
+		for i := 0; i < m; i++ {
+			repeated = append(repeated, uint32(source))
+		}
+
+		chosen := make(map[uint32]bool, m)
+		targets = targets[:0]
+		for len(targets) < m {
+			candidate := repeated[r.Intn(len(repeated))]
+			if !chosen[candidate] {
+				chosen[candidate] = true
+				targets = append(targets, candidate)
+			}
+		}
+	}
+	return G, nil
+}
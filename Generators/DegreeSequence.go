@@ -0,0 +1,190 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Random networks with prescribed degrees: the configuration model and random regular networks
+// Network does not permit self-edges or multiple edges, so the configuration models are the "erased" variants: stub pairings
+// that would produce either are discarded, and realized degrees may fall slightly below those requested.
+// Random regular networks are generated exactly, following Steger, Angelika and Wormald, Nicholas C., "Generating random
+// regular graphs quickly", Combinatorics, Probability and Computing 8, 377-396, 1999
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+)
+
+// maximum number of restarts before RandomRegular gives up
+const maxRegularAttempts = 1000
+
+// Generates an undirected network on vertices 0..len(degrees)-1 by randomly pairing degrees[i] stubs of vertex i.
+// The sum of the degrees must be even.  All edges have weight 1.
+func ConfigurationModel(degrees []int, r *rand.Rand) (*Core.Network, error) {
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+	stubs, err := makeStubs(degrees)
+	if err != nil {
+		return nil, err
+	}
+	if len(stubs)%2 != 0 {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Degree sequence must have an even sum, saw %d", len(stubs)))
+	}
+
+	G := newNetworkWithVertices(len(degrees), false)
+	r.Shuffle(len(stubs), func(i, j int) { stubs[i], stubs[j] = stubs[j], stubs[i] })
+	for i := 0; i < len(stubs); i += 2 {
+		if stubs[i] != stubs[i+1] {
+			_ = G.AddEdge(stubs[i], stubs[i+1], 1.0)
+		}
+	}
+	return G, nil
+}
+
+// Generates a directed network on vertices 0..len(inDegrees)-1 by randomly pairing out-stubs with in-stubs.
+// The sequences must have the same length and the same sum.  All edges have weight 1.
+func DirectedConfigurationModel(inDegrees []int, outDegrees []int, r *rand.Rand) (*Core.Network, error) {
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+	if len(inDegrees) != len(outDegrees) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("In and out degree sequences must be the same length, saw %d and %d", len(inDegrees), len(outDegrees)))
+	}
+	inStubs, err := makeStubs(inDegrees)
+	if err != nil {
+		return nil, err
+	}
+	outStubs, err := makeStubs(outDegrees)
+	if err != nil {
+		return nil, err
+	}
+	if len(inStubs) != len(outStubs) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("In and out degree sequences must have the same sum, saw %d and %d", len(inStubs), len(outStubs)))
+	}
+
+	G := newNetworkWithVertices(len(inDegrees), true)
+	r.Shuffle(len(inStubs), func(i, j int) { inStubs[i], inStubs[j] = inStubs[j], inStubs[i] })
+	for i := 0; i < len(outStubs); i++ {
+		if outStubs[i] != inStubs[i] {
+			_ = G.AddEdge(outStubs[i], inStubs[i], 1.0)
+		}
+	}
+	return G, nil
+}
+
+// Generates an undirected d-regular network on vertices 0..n-1, sampled approximately uniformly.  n*d must be even and d < n.
+// All edges have weight 1.
+func RandomRegular(n int, d int, r *rand.Rand) (*Core.Network, error) {
+	if d < 0 || n < 0 || (n*d)%2 != 0 || (n > 0 && d >= n) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Random regular networks require n*d even and 0 <= d < n, saw n = %d, d = %d", n, d))
+	}
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+
+	for attempt := 0; attempt < maxRegularAttempts; attempt++ {
+		edges, ok := tryRegular(n, d, r)
+		if ok {
+			G := newNetworkWithVertices(n, false)
+			for edge := range edges {
+				_ = G.AddEdge(edge[0], edge[1], 1.0)
+			}
+			return G, nil
+		}
+	}
+	return nil, Core.NewNetworkArgumentError(Sprintf("Unable to generate a %d-regular network of order %d after %d attempts", d, n, maxRegularAttempts))
+}
+
+// one pass of the Steger-Wormald pairing; returns false if the remaining stubs cannot be completed
+func tryRegular(n int, d int, r *rand.Rand) (map[[2]uint32]bool, bool) {
+	edges := make(map[[2]uint32]bool, n*d/2)
+	stubs := make([]uint32, 0, n*d)
+	for i := 0; i < n; i++ {
+		for k := 0; k < d; k++ {
+			stubs = append(stubs, uint32(i))
+		}
+	}
+
+	for len(stubs) > 0 {
+		potential := make(map[uint32]int)
+		r.Shuffle(len(stubs), func(i, j int) { stubs[i], stubs[j] = stubs[j], stubs[i] })
+		for i := 0; i < len(stubs); i += 2 {
+			s1, s2 := stubs[i], stubs[i+1]
+			if s1 > s2 {
+				s1, s2 = s2, s1
+			}
+			if s1 != s2 && !edges[[2]uint32{s1, s2}] {
+				edges[[2]uint32{s1, s2}] = true
+			} else {
+				potential[s1]++
+				potential[s2]++
+			}
+		}
+
+		if !suitable(edges, potential) {
+			return nil, false
+		}
+
+		// rebuild in vertex order so that a given seed always yields the same network
+		stubs = stubs[:0]
+		for vertex := uint32(0); vertex < uint32(n); vertex++ {
+			for k := 0; k < potential[vertex]; k++ {
+				stubs = append(stubs, vertex)
+			}
+		}
+	}
+	return edges, true
+}
+
+// true if at least one pair of vertices with unpaired stubs can still be joined
+func suitable(edges map[[2]uint32]bool, potential map[uint32]int) bool {
+	if len(potential) == 0 {
+		return true
+	}
+	for s1 := range potential {
+		for s2 := range potential {
+			if s1 >= s2 {
+				continue
+			}
+			if !edges[[2]uint32{s1, s2}] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func makeStubs(degrees []int) ([]uint32, error) {
+	if degrees == nil {
+		return nil, Core.NewNetworkArgumentNullError("Degree sequence must be non-null")
+	}
+	stubs := make([]uint32, 0, len(degrees))
+	for vertex, degree := range degrees {
+		if degree < 0 {
+			return nil, Core.NewNetworkArgumentError(Sprintf("Degrees must be non-negative, saw %d for vertex %d", degree, vertex))
+		}
+		for k := 0; k < degree; k++ {
+			stubs = append(stubs, uint32(vertex))
+		}
+	}
+	return stubs, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Erdős–Rényi random graphs, G(n,p) and G(n,m)
+// G(n,p) uses the geometric skipping method of Batagelj and Brandes, "Efficient generation of large random networks",
+// Physical Review E 71, 036113, 2005, so generation is linear in the number of edges rather than quadratic in the number of vertices
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+)
+
+// Generates a G(n,p) network on vertices 0..n-1 where each possible edge is present independently with probability p.
+// All edges have weight 1.
+func ErdosRenyiGnp(n int, p float64, directed bool, r *rand.Rand) (*Core.Network, error) {
+	if n < 0 {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Vertex count must be non-negative, saw %d", n))
+	}
+	if p < 0 || p > 1 {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Edge probability must be in [0,1], saw %f", p))
+	}
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+
+	G := newNetworkWithVertices(n, directed)
+	if p == 0 || n < 2 {
+		return G, nil
+	}
+
+	if p == 1 {
+		for from := 0; from < n; from++ {
+			for to := 0; to < n; to++ {
+				if from == to || (!directed && to < from) {
+					continue
+				}
+				_ = G.AddEdge(uint32(from), uint32(to), 1.0)
+			}
+		}
+		return G, nil
+	}
+
+	lp := math.Log(1.0 - p)
+	if directed {
+		// walk the n(n-1) ordered pairs, skipping a geometrically distributed number of pairs between edges
+		pairs := int64(n) * int64(n-1)
+		idx := int64(-1)
+		for {
+			idx += 1 + int64(math.Log(1.0-r.Float64())/lp)
+			if idx >= pairs {
+				break
+			}
+			from := idx / int64(n-1)
+			to := idx % int64(n-1)
+			if to >= from {
+				to++
+			}
+			_ = G.AddEdge(uint32(from), uint32(to), 1.0)
+		}
+	} else {
+		// walk the lower triangle of the adjacency matrix
+		v := 1
+		w := -1
+		for v < n {
+			w += 1 + int(math.Log(1.0-r.Float64())/lp)
+			for w >= v && v < n {
+				w -= v
+				v++
+			}
+			if v < n {
+				_ = G.AddEdge(uint32(v), uint32(w), 1.0)
+			}
+		}
+	}
+	return G, nil
+}
+
+// Generates a G(n,m) network on vertices 0..n-1 with exactly m edges chosen uniformly at random.
+// All edges have weight 1.
+func ErdosRenyiGnm(n int, m int, directed bool, r *rand.Rand) (*Core.Network, error) {
+	if n < 0 || m < 0 {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Vertex and edge counts must be non-negative, saw %d and %d", n, m))
+	}
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+
+	maxEdges := int64(n) * int64(n-1)
+	if !directed {
+		maxEdges /= 2
+	}
+	if int64(m) > maxEdges {
+		return nil, Core.NewNetworkArgumentError(Sprintf("%d edges requested, but a network of order %d has at most %d", m, n, maxEdges))
+	}
+
+	G := newNetworkWithVertices(n, directed)
+
+	if int64(m)*2 > maxEdges {
+		// dense: a partial shuffle of all pairs avoids a long tail of rejected samples
+		pairs := make([][2]uint32, 0, maxEdges)
+		for from := 0; from < n; from++ {
+			for to := 0; to < n; to++ {
+				if from == to || (!directed && to < from) {
+					continue
+				}
+				pairs = append(pairs, [2]uint32{uint32(from), uint32(to)})
+			}
+		}
+		for i := 0; i < m; i++ {
+			k := i + r.Intn(len(pairs)-i)
+			pairs[i], pairs[k] = pairs[k], pairs[i]
+			_ = G.AddEdge(pairs[i][0], pairs[i][1], 1.0)
+		}
+		return G, nil
+	}
+
+	for added := 0; added < m; {
+		from := uint32(r.Intn(n))
+		to := uint32(r.Intn(n))
+		if from == to || G.HasEdge(from, to) {
+			continue
+		}
+		_ = G.AddEdge(from, to, 1.0)
+		added++
+	}
+	return G, nil
+}
+
+func newNetworkWithVertices(n int, directed bool) *Core.Network {
+	G := Core.NewNetwork(directed)
+	for i := 0; i < n; i++ {
+		G.AddVertex(uint32(i))
+	}
+	return G
+}
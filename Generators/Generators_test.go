@@ -0,0 +1,342 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package Generators
+
+import (
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestErdosRenyi(t *testing.T) {
+	G, err := ErdosRenyiGnp(1000, 0.01, false, rand.New(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if G.Order() != 1000 {
+		t.Errorf("Expected 1000 vertices, found %d", G.Order())
+	}
+	// expected size is 4995, allow roughly five standard deviations
+	if math.Abs(float64(G.Size())-4995) > 350 {
+		t.Errorf("G(n,p) size %d far from expected 4995", G.Size())
+	}
+
+	H, _ := ErdosRenyiGnp(1000, 0.01, false, rand.New(rand.NewSource(42)))
+	diff, _ := Core.Diff(G, H, 0)
+	if !diff.IsEmpty() {
+		t.Error("Same seed produced different G(n,p) networks")
+	}
+
+	D, _ := ErdosRenyiGnp(200, 0.05, true, rand.New(rand.NewSource(7)))
+	if math.Abs(float64(D.Size())-1990) > 220 {
+		t.Errorf("Directed G(n,p) size %d far from expected 1990", D.Size())
+	}
+
+	M, err := ErdosRenyiGnm(100, 250, true, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if M.Size() != 250 {
+		t.Errorf("Expected 250 edges from G(n,m), found %d", M.Size())
+	}
+	M, _ = ErdosRenyiGnm(10, 40, false, rand.New(rand.NewSource(1)))
+	if M.Size() != 40 {
+		t.Errorf("Expected 40 edges from dense G(n,m), found %d", M.Size())
+	}
+	_, err = ErdosRenyiGnm(10, 46, false, rand.New(rand.NewSource(1)))
+	if err == nil {
+		t.Error("Expected an error requesting more edges than possible")
+	}
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	G, err := BarabasiAlbert(500, 3, rand.New(rand.NewSource(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if G.Size() != (500-3)*3 {
+		t.Errorf("Expected %d edges, found %d", (500-3)*3, G.Size())
+	}
+	maxDegree := 0
+	for _, v := range G.Vertices(false) {
+		if G.Degree(v) > maxDegree {
+			maxDegree = G.Degree(v)
+		}
+	}
+	if maxDegree < 20 {
+		t.Errorf("Expected hubs in a preferential attachment network, largest degree is %d", maxDegree)
+	}
+}
+
+func TestWattsStrogatz(t *testing.T) {
+	G, err := WattsStrogatz(100, 4, 0, rand.New(rand.NewSource(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range G.Vertices(false) {
+		if G.Degree(v) != 4 {
+			t.Fatalf("Ring lattice vertex %d has degree %d", v, G.Degree(v))
+		}
+	}
+
+	G, _ = WattsStrogatz(100, 4, 0.3, rand.New(rand.NewSource(5)))
+	if G.Size() != 200 {
+		t.Errorf("Rewiring changed the edge count to %d", G.Size())
+	}
+}
+
+func TestDegreeSequences(t *testing.T) {
+	G, err := RandomRegular(50, 4, rand.New(rand.NewSource(11)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range G.Vertices(false) {
+		if G.Degree(v) != 4 {
+			t.Fatalf("Vertex %d of a 4-regular network has degree %d", v, G.Degree(v))
+		}
+	}
+
+	_, err = RandomRegular(5, 3, rand.New(rand.NewSource(11)))
+	if err == nil {
+		t.Error("Expected an error for an odd stub count")
+	}
+
+	degrees := []int{3, 3, 2, 2, 2, 1, 1}
+	G, err = ConfigurationModel(degrees, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for v, d := range degrees {
+		if G.Degree(uint32(v)) > d {
+			t.Errorf("Vertex %d exceeds its requested degree", v)
+		}
+	}
+
+	D, err := DirectedConfigurationModel([]int{1, 1, 1}, []int{0, 1, 2}, rand.New(rand.NewSource(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if D.OutDegree(0) != 0 {
+		t.Error("Vertex 0 should have no out edges")
+	}
+}
+
+func TestStochasticBlockModel(t *testing.T) {
+	probs := [][]float64{{0.5, 0.01}, {0.01, 0.5}}
+	G, blocks, err := StochasticBlockModel([]int{40, 60}, probs, false, rand.New(rand.NewSource(9)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if G.Order() != 100 || len(blocks) != 2 || len(blocks[1]) != 60 || blocks[1][0] != 40 {
+		t.Error("Block structure not as requested")
+	}
+
+	inside := 0
+	between := 0
+	for _, v := range blocks[0] {
+		for n := range G.GetNeighbors(v) {
+			if n < 40 {
+				inside++
+			} else {
+				between++
+			}
+		}
+	}
+	if inside < 5*between {
+		t.Errorf("Expected edges to concentrate within blocks, found %d inside and %d between", inside, between)
+	}
+
+	_, _, err = StochasticBlockModel([]int{1, 1}, [][]float64{{0, 1}, {0, 0}}, false, rand.New(rand.NewSource(9)))
+	if err == nil {
+		t.Error("Expected an error for an asymmetric undirected probability matrix")
+	}
+}
+
+func TestRandomMultilayer(t *testing.T) {
+	generator := func(coordinates string, r *rand.Rand) (*Core.Network, error) {
+		if coordinates == "A,1" {
+			return ErdosRenyiGnp(30, 0.1, true, r)
+		}
+		return ErdosRenyiGnm(20, 40, true, r)
+	}
+	M, err := RandomMultilayer([]string{"letter", "number"}, [][]string{{"A", "B"}, {"1", "2"}}, true, generator, rand.New(rand.NewSource(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(M.ElementaryLayers()) != 4 {
+		t.Errorf("Expected 4 elementary layers, found %d", len(M.ElementaryLayers()))
+	}
+	if !M.IsNodeAligned() {
+		t.Error("Random multilayer network is not node-aligned")
+	}
+	if M.Order() != 30 {
+		t.Errorf("Expected 30 distinct vertices, found %d", M.Order())
+	}
+}
+
+func TestLFR(t *testing.T) {
+	params := NewLFRParameters(1000, 15, 50, 0.2)
+	params.MinCommunity = 20
+	params.MaxCommunity = 100
+	G, communities, err := LFR(params, rand.New(rand.NewSource(21)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if G.Order() != 1000 {
+		t.Errorf("Expected 1000 vertices, found %d", G.Order())
+	}
+
+	membership := make(map[uint32]int)
+	for label, cmty := range communities {
+		if len(cmty) < 20 || len(cmty) > 100 {
+			t.Errorf("Community %d has size %d outside [20, 100]", label, len(cmty))
+		}
+		for _, v := range cmty {
+			membership[v] = label
+		}
+	}
+	if len(membership) != 1000 {
+		t.Errorf("Expected every vertex in exactly one community, found %d vertices", len(membership))
+	}
+
+	external := 0
+	total := 0
+	for _, v := range G.Vertices(false) {
+		for n := range G.GetNeighbors(v) {
+			total++
+			if membership[n] != membership[v] {
+				external++
+			}
+		}
+	}
+	mixing := float64(external) / float64(total)
+	if math.Abs(mixing-0.2) > 0.05 {
+		t.Errorf("Realized mixing %f far from requested 0.2", mixing)
+	}
+	avg := float64(total) / 1000
+	if math.Abs(avg-15) > 2 {
+		t.Errorf("Realized average degree %f far from requested 15", avg)
+	}
+}
+
+func TestLFROverlappingDirectedWeighted(t *testing.T) {
+	params := NewLFRParameters(500, 10, 30, 0.1)
+	params.MinCommunity = 20
+	params.MaxCommunity = 60
+	params.OverlappingVertices = 50
+	params.Memberships = 2
+	params.Directed = true
+	params.Weighted = true
+	params.WeightMixing = 0.1
+	G, communities, err := LFR(params, rand.New(rand.NewSource(8)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !G.Directed() {
+		t.Error("Expected a directed network")
+	}
+
+	counts := make(map[uint32]int)
+	for _, cmty := range communities {
+		for _, v := range cmty {
+			counts[v]++
+		}
+	}
+	overlapping := 0
+	for _, ct := range counts {
+		if ct == 2 {
+			overlapping++
+		}
+	}
+	if overlapping != 50 {
+		t.Errorf("Expected 50 overlapping vertices, found %d", overlapping)
+	}
+
+	weighted := false
+	for _, v := range G.Vertices(false) {
+		for _, wt := range G.GetNeighbors(v) {
+			if wt != 1.0 {
+				weighted = true
+			}
+		}
+	}
+	if !weighted {
+		t.Error("Expected non-unit weights in a weighted LFR network")
+	}
+
+	_, _, err = LFR(NewLFRParameters(100, 10, 20, 0.0), rand.New(rand.NewSource(8)))
+	if err == nil {
+		t.Error("Expected an error when internal degrees exceed the largest community")
+	}
+}
+
+func TestRewire(t *testing.T) {
+	G, _ := ErdosRenyiGnm(200, 800, true, rand.New(rand.NewSource(12)))
+	H, err := Rewire(G, NewRewireOptions(), rand.New(rand.NewSource(13)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range G.Vertices(false) {
+		if G.InDegree(v) != H.InDegree(v) || G.OutDegree(v) != H.OutDegree(v) {
+			t.Fatalf("Degrees of vertex %d changed by rewiring", v)
+		}
+	}
+	diff, _ := Core.Diff(G, H, 0)
+	if len(diff.RemovedEdges) < G.Size()/2 {
+		t.Errorf("Expected most edges to be rewired, only %d of %d moved", len(diff.RemovedEdges), G.Size())
+	}
+
+	options := NewRewireOptions()
+	options.PreserveConnectivity = true
+	U, _ := WattsStrogatz(100, 4, 0.1, rand.New(rand.NewSource(14)))
+	V, err := Rewire(U, options, rand.New(rand.NewSource(15)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range U.Vertices(false) {
+		if U.Degree(v) != V.Degree(v) {
+			t.Fatalf("Degree of vertex %d changed by rewiring", v)
+		}
+	}
+	if !weaklyConnected(V) {
+		t.Error("Rewiring disconnected the network")
+	}
+}
+
+func TestNullModelEnsemble(t *testing.T) {
+	G, _ := BarabasiAlbert(200, 2, rand.New(rand.NewSource(16)))
+	one, err := NullModelEnsemble(G, 6, NewRewireOptions(), 100, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	four, _ := NullModelEnsemble(G, 6, NewRewireOptions(), 100, 4)
+	if len(one) != 6 || len(four) != 6 {
+		t.Fatal("Wrong ensemble size")
+	}
+	for i := range one {
+		diff, _ := Core.Diff(one[i], four[i], 0)
+		if !diff.IsEmpty() {
+			t.Errorf("Ensemble member %d depends on the number of goroutines", i)
+		}
+	}
+}
@@ -0,0 +1,565 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Lancichinetti–Fortunato–Radicchi benchmark networks with known, optionally overlapping, communities
+// Lancichinetti, Andrea, Fortunato, Santo and Radicchi, Filippo, "Benchmark graphs for testing community detection algorithms",
+// Physical Review E 78, 046110, 2008, with the directed, weighted, and overlapping extensions of Lancichinetti, Andrea and
+// Fortunato, Santo, "Benchmarks for testing community detection algorithms on directed and weighted graphs with overlapping communities",
+// Physical Review E 80, 016118, 2009
+//
+// Edges are placed with the configuration model, erasing the self-edges and multiple edges Network cannot hold, so realized degrees and
+// mixing can fall slightly short of those requested.  Weights are assigned in one pass by splitting each vertex's strength, k^beta, into
+// internal and external parts according to the weight mixing parameter, rather than by the iterative refinement of the reference code.
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// number of times leftover stubs are reshuffled and paired again before they are discarded
+const lfrPairingPasses = 20
+
+// LFR parameters.  Names follow the reference implementation: k, maxk, t1, t2, mu, minc, maxc, on, om, muw, and beta.
+type LFRParameters struct {
+	Order               int     // number of vertices, N
+	AverageDegree       float64 // k (in-degree for directed networks)
+	MaxDegree           int     // maxk
+	DegreeExponent      float64 // t1, exponent of the degree distribution
+	CommunityExponent   float64 // t2, exponent of the community size distribution
+	Mixing              float64 // mu, fraction of each vertex's edges leaving its communities
+	MinCommunity        int     // minc, zero selects the minimum degree
+	MaxCommunity        int     // maxc, zero selects the maximum degree
+	OverlappingVertices int     // on, number of vertices belonging to more than one community
+	Memberships         int     // om, number of communities each overlapping vertex belongs to
+	Directed            bool
+	Weighted            bool
+	WeightMixing        float64 // muw, fraction of each vertex's strength on edges leaving its communities
+	WeightExponent      float64 // beta, strength of a vertex is its degree raised to beta
+}
+
+// parameters with the customary defaults t1 = 2, t2 = 1, no overlap, and beta = 1.5, unweighted and undirected
+func NewLFRParameters(order int, averageDegree float64, maxDegree int, mixing float64) *LFRParameters {
+	p := new(LFRParameters)
+	p.Order = order
+	p.AverageDegree = averageDegree
+	p.MaxDegree = maxDegree
+	p.DegreeExponent = 2.0
+	p.CommunityExponent = 1.0
+	p.Mixing = mixing
+	p.Memberships = 1
+	p.WeightMixing = mixing
+	p.WeightExponent = 1.5
+	return p
+}
+
+// one membership of a vertex in a community, with the share of the vertex's internal degree it receives
+type lfrSlot struct {
+	vertex   int
+	shareIn  int
+	shareOut int
+}
+
+func (slot lfrSlot) share() int {
+	if slot.shareIn > slot.shareOut {
+		return slot.shareIn
+	}
+	return slot.shareOut
+}
+
+// Generates an LFR benchmark network on vertices 0..Order-1 together with its ground-truth communities, keyed by community index,
+// in the same form as the communities returned by ConcurrentSLPA.  Overlapping vertices appear in Memberships communities.
+func LFR(params *LFRParameters, r *rand.Rand) (*Core.Network, map[int][]uint32, error) {
+	if params == nil || r == nil {
+		return nil, nil, Core.NewNetworkArgumentNullError("Parameters and random source must be non-null")
+	}
+	err := params.validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	n := params.Order
+	minDegree, err := solveMinDegree(params.AverageDegree, float64(params.MaxDegree), params.DegreeExponent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// degree sequences; directed networks draw the in-degrees and use a permutation of them as out-degrees so the sums agree
+	inDegrees := make([]int, n)
+	outDegrees := make([]int, n)
+	for i := 0; i < n; i++ {
+		inDegrees[i] = int(math.Round(powerLawSample(minDegree, float64(params.MaxDegree), params.DegreeExponent, r.Float64())))
+	}
+	if params.Directed {
+		copy(outDegrees, inDegrees)
+		r.Shuffle(n, func(i, j int) { outDegrees[i], outDegrees[j] = outDegrees[j], outDegrees[i] })
+	} else {
+		makeEvenSum(inDegrees, params.MaxDegree, r)
+		copy(outDegrees, inDegrees)
+	}
+
+	minCommunity := params.MinCommunity
+	if minCommunity == 0 {
+		minCommunity = int(math.Ceil(minDegree))
+	}
+	maxCommunity := params.MaxCommunity
+	if maxCommunity == 0 {
+		maxCommunity = params.MaxDegree
+	}
+	if minCommunity > maxCommunity || maxCommunity > n {
+		return nil, nil, Core.NewNetworkArgumentError(Sprintf("Community sizes must satisfy minc <= maxc <= N, saw %d, %d, %d", minCommunity, maxCommunity, n))
+	}
+
+	// overlapping vertices are chosen at random; each has Memberships slots, every other vertex has one
+	memberships := make([]int, n)
+	for i := range memberships {
+		memberships[i] = 1
+	}
+	for _, v := range r.Perm(n)[:params.OverlappingVertices] {
+		memberships[v] = params.Memberships
+	}
+
+	slots := make([]lfrSlot, 0, n+params.OverlappingVertices*(params.Memberships-1))
+	maxShare := 0
+	for v := 0; v < n; v++ {
+		internalIn := int(math.Round((1.0 - params.Mixing) * float64(inDegrees[v])))
+		internalOut := int(math.Round((1.0 - params.Mixing) * float64(outDegrees[v])))
+		for m := 0; m < memberships[v]; m++ {
+			slot := lfrSlot{vertex: v, shareIn: splitShare(internalIn, memberships[v], m), shareOut: splitShare(internalOut, memberships[v], m)}
+			if slot.share() > maxShare {
+				maxShare = slot.share()
+			}
+			slots = append(slots, slot)
+		}
+	}
+	if maxShare > maxCommunity-1 {
+		return nil, nil, Core.NewNetworkArgumentError(Sprintf("An internal degree of %d cannot be satisfied by communities of at most %d vertices; raise maxc or mu", maxShare, maxCommunity))
+	}
+
+	sizes, err := communitySizes(len(slots), minCommunity, maxCommunity, params.CommunityExponent, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	members, err := assignSlots(slots, sizes, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	vertexCommunities := make([][]int, n)
+	for c, cmty := range members {
+		for _, slot := range cmty {
+			vertexCommunities[slot.vertex] = append(vertexCommunities[slot.vertex], c)
+		}
+	}
+
+	builder := newLFRBuilder(n, params.Directed, vertexCommunities)
+
+	// internal edges, community by community
+	for _, cmty := range members {
+		outStubs := make([]uint32, 0)
+		inStubs := make([]uint32, 0)
+		for _, slot := range cmty {
+			for k := 0; k < slot.shareOut; k++ {
+				outStubs = append(outStubs, uint32(slot.vertex))
+			}
+			for k := 0; k < slot.shareIn; k++ {
+				inStubs = append(inStubs, uint32(slot.vertex))
+			}
+		}
+		builder.pair(outStubs, inStubs, true, r)
+	}
+
+	// external edges from whatever degree remains
+	outStubs := make([]uint32, 0)
+	inStubs := make([]uint32, 0)
+	for v := 0; v < n; v++ {
+		for k := builder.internalOut[v]; k < outDegrees[v]; k++ {
+			outStubs = append(outStubs, uint32(v))
+		}
+		if params.Directed {
+			for k := builder.internalIn[v]; k < inDegrees[v]; k++ {
+				inStubs = append(inStubs, uint32(v))
+			}
+		}
+	}
+	builder.pair(outStubs, inStubs, false, r)
+
+	G := builder.network(params)
+
+	communities := make(map[int][]uint32, len(members))
+	for c, cmty := range members {
+		list := make([]uint32, len(cmty))
+		for i, slot := range cmty {
+			list[i] = uint32(slot.vertex)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+		communities[c] = list
+	}
+	return G, communities, nil
+}
+
+func (params *LFRParameters) validate() error {
+	if params.Order < 2 {
+		return Core.NewNetworkArgumentError(Sprintf("LFR networks need at least two vertices, saw %d", params.Order))
+	}
+	if params.MaxDegree >= params.Order || float64(params.MaxDegree) < params.AverageDegree || params.AverageDegree < 1 {
+		return Core.NewNetworkArgumentError(Sprintf("Degrees must satisfy 1 <= k <= maxk < N, saw k = %f, maxk = %d, N = %d", params.AverageDegree, params.MaxDegree, params.Order))
+	}
+	if params.DegreeExponent <= 0 || params.CommunityExponent <= 0 {
+		return Core.NewNetworkArgumentError("Degree and community size exponents must be positive")
+	}
+	if params.Mixing < 0 || params.Mixing > 1 || params.WeightMixing < 0 || params.WeightMixing > 1 {
+		return Core.NewNetworkArgumentError(Sprintf("Mixing parameters must be in [0,1], saw mu = %f, muw = %f", params.Mixing, params.WeightMixing))
+	}
+	if params.OverlappingVertices < 0 || params.OverlappingVertices > params.Order {
+		return Core.NewNetworkArgumentError(Sprintf("Overlapping vertex count must be in [0,N], saw %d", params.OverlappingVertices))
+	}
+	if params.Memberships < 1 || (params.OverlappingVertices > 0 && params.Memberships < 2) {
+		return Core.NewNetworkArgumentError(Sprintf("Overlapping vertices must belong to at least two communities, saw om = %d", params.Memberships))
+	}
+	return nil
+}
+
+// split an internal degree as evenly as possible over count memberships, returning the share of the given membership
+func splitShare(degree int, count int, membership int) int {
+	share := degree / count
+	if membership < degree%count {
+		share++
+	}
+	return share
+}
+
+// find the minimum degree giving the requested average for a power law truncated at maxDegree
+func solveMinDegree(average float64, maxDegree float64, exponent float64) (float64, error) {
+	low := 1.0
+	high := maxDegree
+	if powerLawMean(low, maxDegree, exponent) > average {
+		return 0, Core.NewNetworkArgumentError(Sprintf("Average degree %f is too low for maximum degree %f and exponent %f", average, maxDegree, exponent))
+	}
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		if powerLawMean(mid, maxDegree, exponent) < average {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2, nil
+}
+
+// mean of the continuous power law x^-exponent on [min, max]
+func powerLawMean(min float64, max float64, exponent float64) float64 {
+	if max-min < 1e-12 {
+		return min
+	}
+	var numerator, denominator float64
+	if math.Abs(exponent-2) < 1e-9 {
+		numerator = math.Log(max / min)
+	} else {
+		numerator = (math.Pow(max, 2-exponent) - math.Pow(min, 2-exponent)) / (2 - exponent)
+	}
+	if math.Abs(exponent-1) < 1e-9 {
+		denominator = math.Log(max / min)
+	} else {
+		denominator = (math.Pow(max, 1-exponent) - math.Pow(min, 1-exponent)) / (1 - exponent)
+	}
+	return numerator / denominator
+}
+
+// inverse transform sample of the continuous power law x^-exponent on [min, max] for u uniform on [0,1)
+func powerLawSample(min float64, max float64, exponent float64, u float64) float64 {
+	if math.Abs(exponent-1) < 1e-9 {
+		return min * math.Pow(max/min, u)
+	}
+	a := math.Pow(min, 1-exponent)
+	b := math.Pow(max, 1-exponent)
+	return math.Pow(a+(b-a)*u, 1/(1-exponent))
+}
+
+// an undirected degree sequence must have an even sum
+func makeEvenSum(degrees []int, maxDegree int, r *rand.Rand) {
+	sum := 0
+	for _, d := range degrees {
+		sum += d
+	}
+	if sum%2 == 0 {
+		return
+	}
+	for {
+		v := r.Intn(len(degrees))
+		if degrees[v] < maxDegree {
+			degrees[v]++
+			return
+		}
+	}
+}
+
+// draw community sizes until they cover total memberships exactly
+func communitySizes(total int, minSize int, maxSize int, exponent float64, r *rand.Rand) ([]int, error) {
+	if total < minSize {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Minimum community size %d exceeds the %d memberships to place", minSize, total))
+	}
+	sizes := make([]int, 0)
+	sum := 0
+	for sum < total {
+		size := int(math.Round(powerLawSample(float64(minSize), float64(maxSize), exponent, r.Float64())))
+		sizes = append(sizes, size)
+		sum += size
+	}
+
+	// trim the excess from communities that can spare it, largest first
+	excess := sum - total
+	for excess > 0 {
+		largest := 0
+		for i := range sizes {
+			if sizes[i] > sizes[largest] {
+				largest = i
+			}
+		}
+		if sizes[largest] <= minSize {
+			// nothing can shrink; fold the last community into the others
+			last := sizes[len(sizes)-1]
+			sizes = sizes[:len(sizes)-1]
+			excess -= last
+			for excess < 0 {
+				grown := false
+				for i := range sizes {
+					if excess < 0 && sizes[i] < maxSize {
+						sizes[i]++
+						excess++
+						grown = true
+					}
+				}
+				if !grown {
+					return nil, Core.NewNetworkArgumentError("Unable to choose community sizes within [minc, maxc] covering every membership")
+				}
+			}
+			continue
+		}
+		sizes[largest]--
+		excess--
+	}
+	return sizes, nil
+}
+
+// place every slot in a community large enough for its internal degree, evicting a random member when the chosen community is full,
+// as in the reference implementation.  A vertex never holds two slots in the same community.
+func assignSlots(slots []lfrSlot, sizes []int, r *rand.Rand) ([][]lfrSlot, error) {
+	members := make([][]lfrSlot, len(sizes))
+	queue := make([]lfrSlot, len(slots))
+	for i, k := range r.Perm(len(slots)) {
+		queue[i] = slots[k]
+	}
+
+	limit := 100 * len(slots)
+	for steps := 0; len(queue) > 0; steps++ {
+		if steps > limit {
+			return nil, Core.NewNetworkArgumentError("Unable to assign vertices to communities; community sizes are too small for the internal degrees")
+		}
+		slot := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		open := make([]int, 0)
+		full := make([]int, 0)
+		for c, size := range sizes {
+			if size-1 < slot.share() || holdsVertex(members[c], slot.vertex) {
+				continue
+			}
+			if len(members[c]) < size {
+				open = append(open, c)
+			} else {
+				full = append(full, c)
+			}
+		}
+
+		if len(open) > 0 {
+			c := open[r.Intn(len(open))]
+			members[c] = append(members[c], slot)
+		} else if len(full) > 0 {
+			c := full[r.Intn(len(full))]
+			evict := r.Intn(len(members[c]))
+			queue = append(queue, members[c][evict])
+			members[c][evict] = slot
+		} else {
+			return nil, Core.NewNetworkArgumentError(Sprintf("No community can hold vertex %d with internal degree %d", slot.vertex, slot.share()))
+		}
+	}
+	return members, nil
+}
+
+func holdsVertex(cmty []lfrSlot, vertex int) bool {
+	for _, slot := range cmty {
+		if slot.vertex == vertex {
+			return true
+		}
+	}
+	return false
+}
+
+// accumulates edges, keeping them simple and tracking internal degree as they are placed
+type lfrBuilder struct {
+	directed    bool
+	communities [][]int
+	edges       map[[2]uint32]bool // value is true for internal edges
+	internalIn  []int
+	internalOut []int
+}
+
+func newLFRBuilder(n int, directed bool, communities [][]int) *lfrBuilder {
+	b := new(lfrBuilder)
+	b.directed = directed
+	b.communities = communities
+	b.edges = make(map[[2]uint32]bool)
+	b.internalIn = make([]int, n)
+	b.internalOut = make([]int, n)
+	return b
+}
+
+func (b *lfrBuilder) key(from uint32, to uint32) [2]uint32 {
+	if !b.directed && to < from {
+		return [2]uint32{to, from}
+	}
+	return [2]uint32{from, to}
+}
+
+func (b *lfrBuilder) shareCommunity(u uint32, v uint32) bool {
+	for _, cu := range b.communities[u] {
+		for _, cv := range b.communities[v] {
+			if cu == cv {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// add an edge if it is simple; external edges must also join vertices with no community in common
+func (b *lfrBuilder) tryAdd(from uint32, to uint32, internal bool) bool {
+	if from == to {
+		return false
+	}
+	key := b.key(from, to)
+	if _, ok := b.edges[key]; ok {
+		return false
+	}
+	if !internal && b.shareCommunity(from, to) {
+		return false
+	}
+	b.edges[key] = internal
+	if internal {
+		b.internalOut[from]++
+		if b.directed {
+			b.internalIn[to]++
+		} else {
+			b.internalOut[to]++
+		}
+	}
+	return true
+}
+
+// pair stubs, reshuffling the failures for a bounded number of passes.  Undirected networks pair outStubs among themselves and ignore inStubs.
+func (b *lfrBuilder) pair(outStubs []uint32, inStubs []uint32, internal bool, r *rand.Rand) {
+	for pass := 0; pass < lfrPairingPasses; pass++ {
+		if b.directed {
+			if len(outStubs) == 0 || len(inStubs) == 0 {
+				return
+			}
+			r.Shuffle(len(inStubs), func(i, j int) { inStubs[i], inStubs[j] = inStubs[j], inStubs[i] })
+			leftOut := make([]uint32, 0)
+			leftIn := make([]uint32, 0)
+			i := 0
+			for ; i < len(outStubs) && i < len(inStubs); i++ {
+				if !b.tryAdd(outStubs[i], inStubs[i], internal) {
+					leftOut = append(leftOut, outStubs[i])
+					leftIn = append(leftIn, inStubs[i])
+				}
+			}
+			outStubs = append(leftOut, outStubs[i:]...)
+			inStubs = append(leftIn, inStubs[i:]...)
+		} else {
+			if len(outStubs) < 2 {
+				return
+			}
+			r.Shuffle(len(outStubs), func(i, j int) { outStubs[i], outStubs[j] = outStubs[j], outStubs[i] })
+			left := make([]uint32, 0)
+			for i := 0; i+1 < len(outStubs); i += 2 {
+				if !b.tryAdd(outStubs[i], outStubs[i+1], internal) {
+					left = append(left, outStubs[i], outStubs[i+1])
+				}
+			}
+			outStubs = left
+		}
+	}
+}
+
+// build the network, weighting edges from each endpoint's internal or external strength per unit of realized degree
+func (b *lfrBuilder) network(params *LFRParameters) *Core.Network {
+	n := params.Order
+	G := newNetworkWithVertices(n, params.Directed)
+
+	internalDegree := make([]int, n)
+	externalDegree := make([]int, n)
+	for key, internal := range b.edges {
+		if internal {
+			internalDegree[key[0]]++
+			internalDegree[key[1]]++
+		} else {
+			externalDegree[key[0]]++
+			externalDegree[key[1]]++
+		}
+	}
+
+	keys := make([][2]uint32, 0, len(b.edges))
+	for key := range b.edges {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	for _, key := range keys {
+		var wt float32 = 1.0
+		if params.Weighted {
+			internal := b.edges[key]
+			wt = float32((unitStrength(params, internalDegree, externalDegree, key[0], internal) + unitStrength(params, internalDegree, externalDegree, key[1], internal)) / 2)
+		}
+		_ = G.AddEdge(key[0], key[1], wt)
+	}
+	return G
+}
+
+// strength per internal (or external) edge of a vertex whose total strength is its degree raised to beta
+func unitStrength(params *LFRParameters, internalDegree []int, externalDegree []int, v uint32, internal bool) float64 {
+	degree := float64(internalDegree[v] + externalDegree[v])
+	strength := math.Pow(degree, params.WeightExponent)
+	if internal {
+		return (1 - params.WeightMixing) * strength / float64(internalDegree[v])
+	}
+	return params.WeightMixing * strength / float64(externalDegree[v])
+}
@@ -0,0 +1,99 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Random node-aligned multilayer networks assembled from monolayer generators
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"strings"
+)
+
+// produces the network for the elementary layer at the given (comma delimited) aspect coordinates
+type LayerGenerator func(coordinates string, r *rand.Rand) (*Core.Network, error)
+
+// Builds a multilayer network with one elementary layer for every combination of aspect indices, each generated by generator.
+// Any vertex present in one layer but not another is added to the layers lacking it, so the result is node-aligned.
+// Layers are generated in the order of their coordinates, so a given seed always yields the same network.
+func RandomMultilayer(aspects []string, indices [][]string, directed bool, generator LayerGenerator, r *rand.Rand) (*Core.MultilayerNetwork, error) {
+	if aspects == nil || indices == nil || generator == nil {
+		return nil, Core.NewNetworkArgumentNullError("Aspects, indices, and generator must be non-null")
+	}
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+	if len(aspects) == 0 || len(aspects) != len(indices) {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Each of the %d aspects must have a list of indices, saw %d", len(aspects), len(indices)))
+	}
+
+	allCoordinates := layerCoordinates(indices)
+	layers := make([]*Core.Network, len(allCoordinates))
+	allVertices := make(map[uint32]bool)
+	for i, coordinates := range allCoordinates {
+		G, err := generator(coordinates, r)
+		if err != nil {
+			return nil, err
+		}
+		if G == nil {
+			return nil, Core.NewNetworkArgumentNullError(Sprintf("Generator returned a null network for layer %s", coordinates))
+		}
+		if G.Directed() != directed {
+			return nil, Core.NewNetworkArgumentError(Sprintf("Generator returned a network with the wrong value of directed for layer %s", coordinates))
+		}
+		layers[i] = G
+		for _, vertex := range G.Vertices(false) {
+			allVertices[vertex] = true
+		}
+	}
+
+	M := Core.NewMultilayerNetwork(aspects, indices, directed)
+	for i, coordinates := range allCoordinates {
+		for vertex := range allVertices {
+			layers[i].AddVertex(vertex)
+		}
+		_, err := M.AddElementaryLayer(coordinates, layers[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return M, nil
+}
+
+// every combination of aspect indices as comma delimited coordinates, with the last aspect varying fastest
+func layerCoordinates(indices [][]string) []string {
+	retVal := []string{""}
+	for _, aspectIndices := range indices {
+		next := make([]string, 0, len(retVal)*len(aspectIndices))
+		for _, prefix := range retVal {
+			for _, index := range aspectIndices {
+				next = append(next, prefix+index+",")
+			}
+		}
+		retVal = next
+	}
+	for i := range retVal {
+		retVal[i] = strings.TrimSuffix(retVal[i], ",")
+	}
+	return retVal
+}
@@ -0,0 +1,276 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Degree-preserving randomization by double edge swaps, for null models
+// Each swap exchanges the endpoints of two edges, a-b and c-d becoming a-d and c-b, which leaves every vertex's in- and out-degree unchanged.
+// Connectivity is preserved with the windowed check of Gkantsidis, Mihail and Zegura, "The Markov chain simulation method for
+// generating connected power law random graphs", Proceedings of ALENEX, 2003: swaps are made in windows, and a window whose swaps
+// disconnect the network is undone and the window shrunk.
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+type RewireOptions struct {
+	SwapsPerEdge         float64 // successful swaps to make, as a multiple of the number of edges
+	MaxAttemptsPerSwap   int     // attempts allowed per requested swap before giving up
+	PreserveWeights      bool    // if true, each new edge keeps the weight of the edge it replaces at its source; otherwise all weights are 1
+	PreserveConnectivity bool    // if true, the (weakly) connected input stays connected
+}
+
+func NewRewireOptions() *RewireOptions {
+	options := new(RewireOptions)
+	options.SwapsPerEdge = 10
+	options.MaxAttemptsPerSwap = 100
+	options.PreserveWeights = true
+	options.PreserveConnectivity = false
+	return options
+}
+
+// Returns a randomized copy of G with the same in- and out-degree (degree, if undirected) at every vertex.  G is not modified.
+// Swaps that would create self-edges or duplicate edges are rejected, as Network cannot hold them.
+func Rewire(G *Core.Network, options *RewireOptions, r *rand.Rand) (*Core.Network, error) {
+	if G == nil || options == nil || r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network, options, and random source must be non-null")
+	}
+	if options.SwapsPerEdge < 0 || options.MaxAttemptsPerSwap < 1 {
+		return nil, Core.NewNetworkArgumentError("Swaps per edge must be non-negative and attempts per swap positive")
+	}
+	if options.PreserveConnectivity && !weaklyConnected(G) {
+		return nil, Core.NewNetworkArgumentError("Connectivity can only be preserved for a connected network")
+	}
+
+	rewirer := newRewirer(G, options.PreserveWeights)
+	swaps := int(options.SwapsPerEdge * float64(len(rewirer.edges)))
+	if len(rewirer.edges) < 2 || swaps == 0 {
+		return rewirer.H, nil
+	}
+	maxAttempts := swaps * options.MaxAttemptsPerSwap
+
+	if !options.PreserveConnectivity {
+		done := 0
+		for attempts := 0; done < swaps && attempts < maxAttempts; attempts++ {
+			if rewirer.trySwap(r) {
+				done++
+			}
+		}
+		return rewirer.H, nil
+	}
+
+	window := 1
+	done := 0
+	for attempts := 0; done < swaps && attempts < maxAttempts; {
+		rewirer.undo = rewirer.undo[:0]
+		made := 0
+		for made < window && attempts < maxAttempts {
+			attempts++
+			if rewirer.trySwap(r) {
+				made++
+			}
+		}
+		if weaklyConnected(rewirer.H) {
+			done += made
+			window++
+		} else {
+			rewirer.rollback()
+			window = (window + 1) / 2
+		}
+	}
+	return rewirer.H, nil
+}
+
+// Generates count rewired copies of G using concurrentCount goroutines.  Copy i is generated from seed + i, so the ensemble
+// does not depend on the number of goroutines or their scheduling.
+func NullModelEnsemble(G *Core.Network, count int, options *RewireOptions, seed int64, concurrentCount int) ([]*Core.Network, error) {
+	if G == nil || options == nil {
+		return nil, Core.NewNetworkArgumentNullError("Network and options must be non-null")
+	}
+	if count < 0 || concurrentCount < 1 {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Ensemble size must be non-negative and goroutine count positive, saw %d and %d", count, concurrentCount))
+	}
+	if concurrentCount > count {
+		concurrentCount = count
+	}
+
+	retVal := make([]*Core.Network, count)
+	errs := make([]error, count)
+	assignments := make(chan int, count)
+	for i := 0; i < count; i++ {
+		assignments <- i
+	}
+	close(assignments)
+
+	// the input is only read, and each goroutine writes only the slots it was assigned
+	var wg sync.WaitGroup
+	for k := 0; k < concurrentCount; k++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range assignments {
+				retVal[i], errs[i] = Rewire(G, options, rand.New(rand.NewSource(seed+int64(i))))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return retVal, nil
+}
+
+type rewireEdge struct {
+	from   uint32
+	to     uint32
+	weight float32
+}
+
+type rewireSwap struct {
+	i, j  int
+	edgeI rewireEdge
+	edgeJ rewireEdge
+}
+
+// edge list and working copy of the network being rewired
+type rewirer struct {
+	H        *Core.Network
+	directed bool
+	edges    []rewireEdge
+	undo     []rewireSwap
+}
+
+func newRewirer(G *Core.Network, preserveWeights bool) *rewirer {
+	rw := new(rewirer)
+	rw.directed = G.Directed()
+	rw.H = Core.NewNetwork(rw.directed)
+	rw.edges = make([]rewireEdge, 0, G.Size())
+
+	// sorted so that a given seed always yields the same result
+	for _, from := range G.Vertices(true) {
+		rw.H.AddVertex(from)
+		for to, wt := range G.GetNeighbors(from) {
+			if !rw.directed && to < from {
+				continue
+			}
+			if !preserveWeights {
+				wt = 1.0
+			}
+			rw.edges = append(rw.edges, rewireEdge{from: from, to: to, weight: wt})
+		}
+	}
+	sort.Slice(rw.edges, func(i, j int) bool {
+		if rw.edges[i].from != rw.edges[j].from {
+			return rw.edges[i].from < rw.edges[j].from
+		}
+		return rw.edges[i].to < rw.edges[j].to
+	})
+	for _, edge := range rw.edges {
+		_ = rw.H.AddEdge(edge.from, edge.to, edge.weight)
+	}
+	return rw
+}
+
+// attempt one swap of two random edges; a-b, c-d become a-d, c-b
+func (rw *rewirer) trySwap(r *rand.Rand) bool {
+	i := r.Intn(len(rw.edges))
+	j := r.Intn(len(rw.edges))
+	if i == j {
+		return false
+	}
+	edgeI := rw.edges[i]
+	edgeJ := rw.edges[j]
+
+	a, b := edgeI.from, edgeI.to
+	c, d := edgeJ.from, edgeJ.to
+	// undirected edges have no orientation, so flip one at random to reach both possible swaps
+	if !rw.directed && r.Intn(2) == 0 {
+		c, d = d, c
+	}
+
+	if a == d || c == b || a == c || b == d {
+		return false
+	}
+	if rw.H.HasEdge(a, d) || rw.H.HasEdge(c, b) {
+		return false
+	}
+
+	rw.H.RemoveEdge(edgeI.from, edgeI.to)
+	rw.H.RemoveEdge(edgeJ.from, edgeJ.to)
+	rw.edges[i] = rewireEdge{from: a, to: d, weight: edgeI.weight}
+	rw.edges[j] = rewireEdge{from: c, to: b, weight: edgeJ.weight}
+	_ = rw.H.AddEdge(a, d, edgeI.weight)
+	_ = rw.H.AddEdge(c, b, edgeJ.weight)
+	rw.undo = append(rw.undo, rewireSwap{i: i, j: j, edgeI: edgeI, edgeJ: edgeJ})
+	return true
+}
+
+// undo the swaps recorded since the undo log was last cleared, most recent first
+func (rw *rewirer) rollback() {
+	for k := len(rw.undo) - 1; k >= 0; k-- {
+		swap := rw.undo[k]
+		current := rw.edges[swap.i]
+		rw.H.RemoveEdge(current.from, current.to)
+		current = rw.edges[swap.j]
+		rw.H.RemoveEdge(current.from, current.to)
+		rw.edges[swap.i] = swap.edgeI
+		rw.edges[swap.j] = swap.edgeJ
+		_ = rw.H.AddEdge(swap.edgeI.from, swap.edgeI.to, swap.edgeI.weight)
+		_ = rw.H.AddEdge(swap.edgeJ.from, swap.edgeJ.to, swap.edgeJ.weight)
+	}
+	rw.undo = rw.undo[:0]
+}
+
+// true if every vertex is reachable from every other ignoring edge direction
+func weaklyConnected(G *Core.Network) bool {
+	if G.Order() == 0 {
+		return true
+	}
+	start, _ := G.StartingVertex(false)
+	seen := map[uint32]bool{start: true}
+	frontier := []uint32{start}
+	for len(frontier) > 0 {
+		v := frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		for n := range G.GetNeighbors(v) {
+			if !seen[n] {
+				seen[n] = true
+				frontier = append(frontier, n)
+			}
+		}
+		if G.Directed() {
+			for n := range G.GetSources(v) {
+				if !seen[n] {
+					seen[n] = true
+					frontier = append(frontier, n)
+				}
+			}
+		}
+	}
+	return len(seen) == G.Order()
+}
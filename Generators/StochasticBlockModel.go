@@ -0,0 +1,95 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Stochastic block model
+// Holland, Paul W., Laskey, Kathryn Blackmond and Leinhardt, Samuel, "Stochastic blockmodels: First steps", Social Networks 5, 109-137, 1983
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+)
+
+// Generates a network whose vertices are divided into blocks of the given sizes, numbered consecutively from 0 block by block.
+// An edge from a vertex in block i to a vertex in block j is present with probability probs[i][j]; for undirected networks
+// probs must be symmetric.  The block memberships are returned in the same form as the communities found by ConcurrentSLPA,
+// keyed by block index.  All edges have weight 1.
+func StochasticBlockModel(sizes []int, probs [][]float64, directed bool, r *rand.Rand) (*Core.Network, map[int][]uint32, error) {
+	if sizes == nil || probs == nil {
+		return nil, nil, Core.NewNetworkArgumentNullError("Block sizes and probabilities must be non-null")
+	}
+	if r == nil {
+		return nil, nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+	blockCt := len(sizes)
+	if len(probs) != blockCt {
+		return nil, nil, Core.NewNetworkArgumentError(Sprintf("Probability matrix must be %d x %d, saw %d rows", blockCt, blockCt, len(probs)))
+	}
+	for i := 0; i < blockCt; i++ {
+		if sizes[i] < 0 {
+			return nil, nil, Core.NewNetworkArgumentError(Sprintf("Block sizes must be non-negative, saw %d for block %d", sizes[i], i))
+		}
+		if len(probs[i]) != blockCt {
+			return nil, nil, Core.NewNetworkArgumentError(Sprintf("Probability matrix must be %d x %d, saw %d columns in row %d", blockCt, blockCt, len(probs[i]), i))
+		}
+		for j := 0; j < blockCt; j++ {
+			if probs[i][j] < 0 || probs[i][j] > 1 {
+				return nil, nil, Core.NewNetworkArgumentError(Sprintf("Probabilities must be in [0,1], saw %f at %d, %d", probs[i][j], i, j))
+			}
+			if !directed && probs[i][j] != probs[j][i] {
+				return nil, nil, Core.NewNetworkArgumentError(Sprintf("Probability matrix of an undirected network must be symmetric, differs at %d, %d", i, j))
+			}
+		}
+	}
+
+	blocks := make(map[int][]uint32, blockCt)
+	membership := make([]int, 0)
+	next := uint32(0)
+	for i, size := range sizes {
+		block := make([]uint32, size)
+		for k := 0; k < size; k++ {
+			block[k] = next
+			membership = append(membership, i)
+			next++
+		}
+		blocks[i] = block
+	}
+
+	n := len(membership)
+	G := newNetworkWithVertices(n, directed)
+	for from := 0; from < n; from++ {
+		start := 0
+		if !directed {
+			start = from + 1
+		}
+		for to := start; to < n; to++ {
+			if from == to {
+				continue
+			}
+			if r.Float64() < probs[membership[from]][membership[to]] {
+				_ = G.AddEdge(uint32(from), uint32(to), 1.0)
+			}
+		}
+	}
+	return G, blocks, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 - 2019  Stephen T. Mohr
+// MIT License
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Watts–Strogatz small-world networks
+// Watts, Duncan J. and Strogatz, Steven H., "Collective dynamics of 'small-world' networks", Nature 393, 440-442, 1998
+
+package Generators
+
+import (
+	. "fmt"
+	"github.com/smohr1824/Networks/Core"
+	"math/rand"
+)
+
+// Generates an undirected small-world network on vertices 0..n-1.  Each vertex starts joined to its k nearest neighbors on a ring
+// (k/2 on each side, so k must be even), then each edge is rewired to a uniformly chosen vertex with probability beta, avoiding
+// self-edges and duplicate edges.  All edges have weight 1.
+func WattsStrogatz(n int, k int, beta float64, r *rand.Rand) (*Core.Network, error) {
+	if k < 0 || k%2 != 0 || k >= n {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Neighbor count must be even and less than the vertex count, saw k = %d, n = %d", k, n))
+	}
+	if beta < 0 || beta > 1 {
+		return nil, Core.NewNetworkArgumentError(Sprintf("Rewiring probability must be in [0,1], saw %f", beta))
+	}
+	if r == nil {
+		return nil, Core.NewNetworkArgumentNullError("Random source must be non-null")
+	}
+
+	G := newNetworkWithVertices(n, false)
+	for j := 1; j <= k/2; j++ {
+		for u := 0; u < n; u++ {
+			_ = G.AddEdge(uint32(u), uint32((u+j)%n), 1.0)
+		}
+	}
+
+	// rewire one ring at a time, as in the original paper
+	for j := 1; j <= k/2; j++ {
+		for u := 0; u < n; u++ {
+			v := uint32((u + j) % n)
+			if r.Float64() >= beta || !G.HasEdge(uint32(u), v) {
+				continue
+			}
+			// a vertex joined to everything cannot be rewired
+			if len(G.GetNeighbors(uint32(u))) >= n-1 {
+				continue
+			}
+			w := uint32(r.Intn(n))
+			for w == uint32(u) || G.HasEdge(uint32(u), w) {
+				w = uint32(r.Intn(n))
+			}
+			G.RemoveEdge(uint32(u), v)
+			_ = G.AddEdge(uint32(u), w, 1.0)
+		}
+	}
+	return G, nil
+}